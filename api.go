@@ -1,7 +1,6 @@
 package watercrawl
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -9,8 +8,6 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
-	"strings"
-	"time"
 )
 
 // GetCrawlRequests retrieves a paginated list of crawl requests
@@ -19,22 +16,12 @@ func (c *Client) GetCrawlRequests(ctx context.Context, page, pageSize int) (*Cra
 	queryParams.Set("page", strconv.Itoa(page))
 	queryParams.Set("page_size", strconv.Itoa(pageSize))
 
-	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/core/crawl-requests/", queryParams, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	var result CrawlRequestList
-	if err := c.processResponse(resp, &result); err != nil {
-		return nil, err
-	}
-
-	return &result, nil
+	return c.getCrawlRequestsPage(ctx, queryParams)
 }
 
 // GetCrawlRequest retrieves a specific crawl request by ID
 func (c *Client) GetCrawlRequest(ctx context.Context, id string) (*CrawlRequest, error) {
-	resp, err := c.doRequest(ctx, http.MethodGet, fmt.Sprintf("/api/v1/core/crawl-requests/%s/", id), nil, nil)
+	resp, err := c.doRequest(ctx, http.MethodGet, c.apiPath("core/crawl-requests/%s/", id), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -50,44 +37,17 @@ func (c *Client) GetCrawlRequest(ctx context.Context, id string) (*CrawlRequest,
 // CreateCrawlRequest creates a new crawl request
 func (c *Client) CreateCrawlRequest(ctx context.Context, input CreateCrawlRequestInput) (*CrawlRequest, error) {
 	// Validate input
-	if input.URL == nil {
-		return nil, &ValidationError{
-			Field:   "url",
-			Message: "URL is required",
-		}
+	if err := input.URL.Validate(); err != nil {
+		return nil, err
 	}
 
-	switch v := input.URL.(type) {
-	case string:
-		if v == "" {
-			return nil, &ValidationError{
-				Field:   "url",
-				Message: "URL cannot be empty",
-			}
-		}
-	case []string:
-		if len(v) == 0 {
-			return nil, &ValidationError{
-				Field:   "url",
-				Message: "URL list cannot be empty",
-			}
-		}
-		for i, u := range v {
-			if u == "" {
-				return nil, &ValidationError{
-					Field:   fmt.Sprintf("url[%d]", i),
-					Message: "URL cannot be empty",
-				}
-			}
-		}
-	default:
-		return nil, &ValidationError{
-			Field:   "url",
-			Message: "URL must be a string or array of strings",
-		}
+	var header http.Header
+	if input.IdempotencyKey != "" {
+		header = http.Header{}
+		header.Set(IdempotencyKeyHeader, input.IdempotencyKey)
 	}
 
-	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/core/crawl-requests/", nil, input)
+	resp, err := c.doRequestWithHeader(ctx, http.MethodPost, c.apiPath("core/crawl-requests/"), nil, input, header)
 	if err != nil {
 		return nil, err
 	}
@@ -102,7 +62,7 @@ func (c *Client) CreateCrawlRequest(ctx context.Context, input CreateCrawlReques
 
 // StopCrawlRequest stops a specific crawl request
 func (c *Client) StopCrawlRequest(ctx context.Context, id string) error {
-	resp, err := c.doRequest(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/core/crawl-requests/%s/", id), nil, nil)
+	resp, err := c.doRequest(ctx, http.MethodDelete, c.apiPath("core/crawl-requests/%s/", id), nil, nil)
 	if err != nil {
 		return err
 	}
@@ -112,13 +72,13 @@ func (c *Client) StopCrawlRequest(ctx context.Context, id string) error {
 
 // DownloadCrawlRequest downloads the results of a crawl request
 func (c *Client) DownloadCrawlRequest(ctx context.Context, id string) (map[string]interface{}, error) {
-	resp, err := c.doRequest(ctx, http.MethodGet, fmt.Sprintf("/api/v1/core/crawl-requests/%s/download/", id), nil, nil)
+	resp, err := c.doRequest(ctx, http.MethodGet, c.apiPath("core/crawl-requests/%s/download/", id), nil, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
-			fmt.Printf("Error closing response body: %v\n", err)
+			c.logger.Warnf("Error closing response body: %v", err)
 		}
 	}()
 
@@ -128,6 +88,10 @@ func (c *Client) DownloadCrawlRequest(ctx context.Context, id string) (map[strin
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	if resp.StatusCode >= 400 {
+		return nil, c.errorFromResponse(resp, body)
+	}
+
 	// First try to unmarshal as object
 	var resultObj map[string]interface{}
 	if err := json.Unmarshal(body, &resultObj); err != nil {
@@ -146,102 +110,17 @@ func (c *Client) DownloadCrawlRequest(ctx context.Context, id string) (map[strin
 	return resultObj, nil
 }
 
-// MonitorCrawlRequest monitors the status of a crawl request and returns a channel of events
+// MonitorCrawlRequest monitors the status of a crawl request and returns a channel of events.
+// Dropped streams are resumed according to the client's reconnect policy; the
+// channel is closed once the crawl reaches a terminal status or ctx is done.
+// Use WatchCrawlRequest to learn why monitoring ended.
 func (c *Client) MonitorCrawlRequest(ctx context.Context, id string, download bool) (<-chan *EventStreamMessage, error) {
-	resp, err := c.doRequest(ctx, http.MethodGet, fmt.Sprintf("/api/v1/core/crawl-requests/%s/status/", id), nil, nil)
+	monitor, err := c.WatchCrawlRequest(ctx, id, &MonitorOptions{Download: download})
 	if err != nil {
 		return nil, err
 	}
 
-	eventChan := make(chan *EventStreamMessage)
-
-	go func() {
-		defer close(eventChan)
-		defer func() {
-			if err := resp.Body.Close(); err != nil {
-				fmt.Printf("Error closing response body: %v\n", err)
-			}
-		}()
-
-		// Create a reader for the response body
-		reader := bufio.NewReader(resp.Body)
-
-		for {
-			select {
-			case <-ctx.Done():
-				fmt.Println("Context done, stopping monitoring")
-				return
-			default:
-				// Read line by line
-				line, err := reader.ReadString('\n')
-				if err != nil {
-					if err != io.EOF {
-						fmt.Printf("Error reading line: %v\n", err)
-					} else {
-						fmt.Println("End of stream (EOF)")
-					}
-					return
-				}
-
-				// Trim whitespace
-				line = strings.TrimSpace(line)
-
-				// Skip empty lines
-				if line == "" {
-					continue
-				}
-
-				fmt.Printf("Received line: %s\n", line)
-
-				// Check if it's an SSE data line
-				if strings.HasPrefix(line, "data:") {
-					// Extract the JSON payload
-					jsonData := strings.TrimPrefix(line, "data:")
-					jsonData = strings.TrimSpace(jsonData)
-
-					// Parse the JSON
-					var event EventStreamMessage
-					if err := json.Unmarshal([]byte(jsonData), &event); err != nil {
-						fmt.Printf("Error parsing JSON from SSE: %v\n", err)
-						continue
-					}
-
-					// Process the event
-					if download && event.Type == "result" {
-						// Download the result data if requested
-						if _, ok := event.Data.(map[string]interface{}); ok {
-							// Create a new timeout context for download operation
-							downloadCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-							downloadedData, err := c.DownloadCrawlRequest(downloadCtx, id)
-							cancel()
-
-							if err == nil {
-								// Replace the entire event data with downloaded data
-								event.Data = downloadedData
-								fmt.Println("Successfully downloaded result data")
-							} else {
-								fmt.Printf("Error downloading result data: %v\n", err)
-							}
-						}
-					}
-
-					// Try to send the event, respecting context cancellation
-					select {
-					case eventChan <- &event:
-						// Event sent successfully
-					case <-ctx.Done():
-						fmt.Println("Context done while sending event")
-						return
-					}
-				} else {
-					// Handle other types of SSE lines if needed (like "id:" or "event:")
-					fmt.Printf("Non-data SSE line: %s\n", line)
-				}
-			}
-		}
-	}()
-
-	return eventChan, nil
+	return monitor.Events(), nil
 }
 
 // GetCrawlRequestResults retrieves the results of a crawl request
@@ -250,141 +129,27 @@ func (c *Client) GetCrawlRequestResults(ctx context.Context, id string, page, pa
 	queryParams.Set("page", strconv.Itoa(page))
 	queryParams.Set("page_size", strconv.Itoa(pageSize))
 
-	resp, err := c.doRequest(ctx, http.MethodGet, fmt.Sprintf("/api/v1/core/crawl-requests/%s/results/", id), queryParams, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	var result CrawlResultList
-	if err := c.processResponse(resp, &result); err != nil {
-		return nil, err
-	}
-
-	return &result, nil
+	return c.getCrawlRequestResultsPage(ctx, id, queryParams)
 }
 
-// ScrapeURL performs a single URL scrape
-func (c *Client) ScrapeURL(ctx context.Context, url string, pageOptions, pluginOptions map[string]interface{}, sync, download bool) (map[string]interface{}, error) {
-	input := CreateCrawlRequestInput{
-		URL: url,
-		Options: CrawlOptions{
-			SpiderOptions: map[string]interface{}{
-				"allowed_domains": []string{"*"},
-			},
-			PageOptions:   pageOptions,
-			PluginOptions: pluginOptions,
-		},
-	}
-
-	result, err := c.CreateCrawlRequest(ctx, input)
-	if err != nil {
-		return nil, err
-	}
-
-	fmt.Printf("Crawl request created with UUID: %s, Status: %s\n", result.UUID, result.Status)
-
-	if !sync {
-		return map[string]interface{}{
-			"uuid":   result.UUID,
-			"status": result.Status,
-		}, nil
-	}
-
-	fmt.Println("Monitoring crawl request...")
-	events, err := c.MonitorCrawlRequest(ctx, result.UUID, download)
+// ScrapeURL performs a single URL scrape.
+// pageOptions and pluginOptions may be nil to use the server defaults.
+// The shape of the returned map depends on how the result was obtained; use
+// Scrape for a typed result. In sync mode, the crawl request is stopped if
+// ctx ends first, see RunOwnedCrawl.
+func (c *Client) ScrapeURL(ctx context.Context, url string, pageOptions *PageOptions, pluginOptions *PluginOptions, sync, download bool) (map[string]interface{}, error) {
+	opts := &ScrapeOptions{
+		PageOptions: pageOptions,
+		Async:       !sync,
+		Download:    download,
+	}
+	if pluginOptions != nil {
+		opts.PluginOptions = *pluginOptions
+	}
+	result, err := c.Scrape(ctx, url, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	fmt.Println("Waiting for events...")
-	eventCount := 0
-	var lastProgress float64
-	var lastError interface{}
-	var lastStateData map[string]interface{}
-
-	for event := range events {
-		eventCount++
-		fmt.Printf("Received event #%d of type: %s\n", eventCount, event.Type)
-
-		switch event.Type {
-		case "result":
-			fmt.Println("Found result event!")
-			if data, ok := event.Data.(map[string]interface{}); ok {
-				return data, nil
-			} else {
-				fmt.Printf("Warning: result event has unexpected data type: %T\n", event.Data)
-			}
-		case "error":
-			fmt.Printf("Error event received: %v\n", event.Data)
-			lastError = event.Data
-		case "progress":
-			if progressData, ok := event.Data.(map[string]interface{}); ok {
-				if progress, ok := progressData["progress"].(float64); ok {
-					lastProgress = progress
-					fmt.Printf("Progress: %.2f%%\n", progress)
-				}
-			}
-		case "state":
-			// Save state data in case we don't get a result event
-			if stateData, ok := event.Data.(map[string]interface{}); ok {
-				lastStateData = stateData
-
-				// Check if status is "completed" or "failed"
-				if status, ok := stateData["status"].(string); ok {
-					if status == "completed" {
-						fmt.Println("Crawl completed according to state event")
-						if download {
-							// Try to download the results
-							downloadCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-							downloadData, err := c.DownloadCrawlRequest(downloadCtx, result.UUID)
-							cancel()
-
-							if err == nil {
-								return downloadData, nil
-							} else {
-								fmt.Printf("Error downloading result data: %v\n", err)
-							}
-						}
-						// If download failed or wasn't requested, return the state data
-						return stateData, nil
-					} else if status == "failed" {
-						return nil, fmt.Errorf("crawl failed with status: %s", status)
-					}
-				}
-			}
-		case "completed":
-			fmt.Println("Crawl completed event received")
-			// If we receive a completed event but haven't received a result yet, try to download
-			if download {
-				fmt.Println("Attempting to download final results...")
-				downloadCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-				downloadedData, err := c.DownloadCrawlRequest(downloadCtx, result.UUID)
-				cancel()
-
-				if err == nil && len(downloadedData) > 0 {
-					fmt.Println("Successfully downloaded final results")
-					return downloadedData, nil
-				} else if err != nil {
-					fmt.Printf("Error downloading final results: %v\n", err)
-				} else {
-					fmt.Println("Downloaded results were empty")
-				}
-			}
-		}
-	}
-
-	// If we have state data but no result, return the state data
-	if lastStateData != nil {
-		return lastStateData, nil
-	}
-
-	// If we get here, we didn't receive a valid result
-	if eventCount == 0 {
-		return nil, fmt.Errorf("no events received from crawl request (timeout or connection error)")
-	} else if lastError != nil {
-		return nil, fmt.Errorf("crawl request failed with error: %v", lastError)
-	} else {
-		return nil, fmt.Errorf("received %d events (last progress: %.2f%%) but no valid result event",
-			eventCount, lastProgress)
-	}
+	return result.Data, nil
 }
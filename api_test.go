@@ -96,10 +96,10 @@ func TestClient_CreateCrawlRequest(t *testing.T) {
 	ctx := context.Background()
 
 	input := CreateCrawlRequestInput{
-		URL: "https://example.com",
+		URL: URLs{"https://example.com"},
 		Options: CrawlOptions{
-			SpiderOptions: map[string]interface{}{
-				"allowed_domains": []string{"example.com"},
+			SpiderOptions: SpiderOptions{
+				AllowedDomains: []string{"example.com"},
 			},
 		},
 	}
@@ -274,6 +274,31 @@ func TestClient_ScrapeURL_StateOnly(t *testing.T) {
 	}
 }
 
+func TestClient_ScrapeURL_Async(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/core/crawl-requests/" {
+			t.Errorf("Expected path /api/v1/core/crawl-requests/, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"uuid":"test-uuid","status":"new"}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	result, err := client.ScrapeURL(context.Background(), "https://example.com", nil, nil, false, false)
+	if err != nil {
+		t.Fatalf("ScrapeURL() error = %v", err)
+	}
+
+	if uuid, ok := result["uuid"].(string); !ok || uuid != "test-uuid" {
+		t.Errorf("ScrapeURL() uuid = %#v, want string test-uuid", result["uuid"])
+	}
+	if status, ok := result["status"].(string); !ok || status != "new" {
+		t.Errorf("ScrapeURL() status = %#v, want string new", result["status"])
+	}
+}
+
 func TestClient_MonitorCrawlRequest(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v1/core/crawl-requests/test-uuid/status/" {
@@ -492,7 +517,7 @@ func TestClient_CreateCrawlRequest_Validation(t *testing.T) {
 		{
 			name: "empty string URL",
 			input: CreateCrawlRequestInput{
-				URL:     "",
+				URL:     URLs{""},
 				Options: CrawlOptions{},
 			},
 			expectedError: "watercrawl: validation error: url: URL cannot be empty",
@@ -514,12 +539,20 @@ func TestClient_CreateCrawlRequest_Validation(t *testing.T) {
 			expectedError: "watercrawl: validation error: url[1]: URL cannot be empty",
 		},
 		{
-			name: "invalid URL type",
+			name: "relative URL",
+			input: CreateCrawlRequestInput{
+				URL:     URLs{"example.com/page"},
+				Options: CrawlOptions{},
+			},
+			expectedError: "watercrawl: validation error: url: URL must be an absolute http or https URL",
+		},
+		{
+			name: "unsupported scheme in list",
 			input: CreateCrawlRequestInput{
-				URL:     123,
+				URL:     URLs{"https://example.com", "ftp://example.com/file"},
 				Options: CrawlOptions{},
 			},
-			expectedError: "watercrawl: validation error: url: URL must be a string or array of strings",
+			expectedError: "watercrawl: validation error: url[1]: URL must be an absolute http or https URL",
 		},
 	}
 
@@ -0,0 +1,369 @@
+package watercrawl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// DefaultBatchSize is the number of URLs BatchScrape submits per crawl request
+// when BatchScrapeOptions.BatchSize is not set
+const DefaultBatchSize = 100
+
+// DefaultBatchConcurrency is the number of crawl requests BatchScrape runs
+// at once when BatchScrapeOptions.Concurrency is not set
+const DefaultBatchConcurrency = 2
+
+// ErrNoResult is reported for a URL of a batch that produced no result
+var ErrNoResult = errors.New("watercrawl: no result for URL")
+
+// redirectSourceKeys are result metadata keys that may hold the URL a page
+// was requested as, when the API reports it
+var redirectSourceKeys = []string{"source_url", "original_url", "requested_url"}
+
+// BatchScrapeOptions configures BatchScrape
+type BatchScrapeOptions struct {
+	// PageOptions may be nil to use the server defaults
+	PageOptions   *PageOptions
+	PluginOptions PluginOptions
+
+	// SpiderOptions may be nil. Fields left unset default to scraping each
+	// URL exactly once: any domain, depth 0 and a page limit of the number
+	// of URLs in the crawl request.
+	SpiderOptions *SpiderOptions
+
+	// BatchSize is the maximum number of URLs per crawl request,
+	// DefaultBatchSize if 0. Set it to the server's limit.
+	BatchSize int
+
+	// Concurrency is the maximum number of crawl requests in flight,
+	// DefaultBatchConcurrency if 0
+	Concurrency int
+}
+
+// BatchScrapeResult is the outcome of one URL of a batch
+type BatchScrapeResult struct {
+	// URL is the URL as passed to BatchScrape
+	URL string
+
+	// Result is the crawl result, nil if Err is set
+	Result *CrawlResult
+
+	// Err is the error for this URL: a *ValidationError for an invalid URL,
+	// the error of the crawl request it was submitted with, or ErrNoResult
+	Err error
+
+	// CrawlRequest is the UUID of the crawl request the URL was submitted with
+	CrawlRequest string
+
+	// Redirected is true when the result is for another URL than requested,
+	// usually because the page redirected
+	Redirected bool
+}
+
+// BatchScrape scrapes many URLs, submitting them in crawl requests of at most
+// opts.BatchSize URLs, opts.Concurrency at a time, and returns the outcome of
+// each URL keyed by the URL as passed. Results are matched to URLs after
+// normalization (case of the scheme and host, default ports, trailing
+// slashes, query order and fragments), then by redirect source when the API
+// reports one, and finally by elimination when a single URL and a single
+// result remain on the same site.
+//
+// Errors of individual URLs are reported in their result. The error return is
+// only set when ctx is done, in which case the results gathered so far are
+// returned as well, the crawl requests still running are abandoned as
+// described in RunOwnedCrawl and the URLs not submitted yet fail with the
+// context error. opts may be nil.
+func (c *Client) BatchScrape(ctx context.Context, urls []string, opts *BatchScrapeOptions) (map[string]*BatchScrapeResult, error) {
+	if opts == nil {
+		opts = &BatchScrapeOptions{}
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+
+	results := make(map[string]*BatchScrapeResult, len(urls))
+	var valid []string
+	for _, u := range urls {
+		if _, ok := results[u]; ok {
+			continue
+		}
+		result := &BatchScrapeResult{URL: u}
+		results[u] = result
+
+		if err := (URLs{u}).Validate(); err != nil {
+			result.Err = err
+			continue
+		}
+		valid = append(valid, u)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for start := 0; start < len(valid); start += batchSize {
+		end := start + batchSize
+		if end > len(valid) {
+			end = len(valid)
+		}
+		batch := valid[start:end]
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if err := ctx.Err(); err != nil {
+			// Submit nothing more once ctx is done
+			mu.Lock()
+			for _, u := range valid[start:] {
+				results[u].Err = err
+			}
+			mu.Unlock()
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			outcomes := c.scrapeBatch(ctx, batch, opts)
+
+			mu.Lock()
+			defer mu.Unlock()
+			for u, outcome := range outcomes {
+				*results[u] = *outcome
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results, ctx.Err()
+}
+
+// scrapeBatch submits one crawl request for urls and returns the outcome of each
+func (c *Client) scrapeBatch(ctx context.Context, urls []string, opts *BatchScrapeOptions) map[string]*BatchScrapeResult {
+	outcomes := make(map[string]*BatchScrapeResult, len(urls))
+	// withError sets err on the URLs without a result
+	withError := func(err error) map[string]*BatchScrapeResult {
+		for _, u := range urls {
+			if outcomes[u] == nil {
+				outcomes[u] = &BatchScrapeResult{URL: u}
+			}
+			if outcomes[u].Result == nil {
+				outcomes[u].Err = err
+			}
+		}
+		return outcomes
+	}
+
+	input := CreateCrawlRequestInput{
+		URL: URLs(urls),
+		Options: CrawlOptions{
+			SpiderOptions: batchSpiderOptions(opts.SpiderOptions, len(urls)),
+			PluginOptions: opts.PluginOptions,
+		},
+	}
+	if opts.PageOptions != nil {
+		input.Options.PageOptions = *opts.PageOptions
+	}
+
+	request, err := c.CreateCrawlRequest(ctx, input)
+	if err != nil {
+		return withError(err)
+	}
+	for _, u := range urls {
+		outcomes[u] = &BatchScrapeResult{URL: u, CrawlRequest: request.UUID}
+	}
+
+	c.logger.Infof("Batch crawl request %s created for %d URLs", request.UUID, len(urls))
+
+	// Results are collected even when the crawl failed, some pages may have succeeded
+	var crawled []CrawlResult
+	err = c.ownCrawl(ctx, request.UUID, func() error {
+		monitor, err := c.WatchCrawlRequest(ctx, request.UUID, nil)
+		if err != nil {
+			return err
+		}
+		for range monitor.Events() {
+			// Only the end of the crawl matters, results are listed below
+		}
+		if err := monitor.Err(); err != nil {
+			return fmt.Errorf("monitoring crawl request %s: %w", request.UUID, err)
+		}
+
+		it := c.IterateCrawlRequestResults(request.UUID, IteratorOptions{})
+		for it.Next(ctx) {
+			crawled = append(crawled, it.Value())
+		}
+		return it.Err()
+	})
+	if err != nil {
+		return withError(err)
+	}
+
+	matchBatchResults(outcomes, urls, crawled)
+
+	request, err = c.GetCrawlRequest(ctx, request.UUID)
+	switch {
+	case err != nil:
+		return withError(err)
+	case !request.Status.IsSuccess():
+		return withError(fmt.Errorf("crawl failed with status: %s", request.Status))
+	}
+	return withError(ErrNoResult)
+}
+
+// batchSpiderOptions returns the spider options of a crawl request of n
+// URLs, filling the fields not set in opts so each URL is scraped once
+func batchSpiderOptions(opts *SpiderOptions, n int) SpiderOptions {
+	var spider SpiderOptions
+	if opts != nil {
+		spider = *opts
+	}
+	if len(spider.AllowedDomains) == 0 {
+		spider.AllowedDomains = []string{"*"}
+	}
+	if spider.MaxDepth == nil {
+		spider.MaxDepth = Int(0)
+	}
+	if spider.PageLimit == 0 {
+		spider.PageLimit = n
+	}
+	return spider
+}
+
+// matchBatchResults assigns crawled results to the outcomes of the URLs they were requested as
+func matchBatchResults(outcomes map[string]*BatchScrapeResult, urls []string, crawled []CrawlResult) {
+	byNormalized := map[string][]string{}
+	for _, u := range urls {
+		key := normalizeURL(u)
+		byNormalized[key] = append(byNormalized[key], u)
+	}
+
+	assign := func(key string, result *CrawlResult, redirected bool) bool {
+		matched := false
+		for _, u := range byNormalized[key] {
+			if outcomes[u].Result == nil {
+				outcomes[u].Result = result
+				outcomes[u].Redirected = redirected
+				matched = true
+			}
+		}
+		return matched
+	}
+
+	var unmatched []*CrawlResult
+	for i := range crawled {
+		result := &crawled[i]
+		if assign(normalizeURL(result.URL), result, false) {
+			continue
+		}
+
+		matched := false
+		for _, source := range redirectSources(result) {
+			if assign(normalizeURL(source), result, true) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			unmatched = append(unmatched, result)
+		}
+	}
+
+	// Match the remaining results by elimination within each site. Results
+	// are never matched across sites: a leftover page of one site says
+	// nothing about a URL of another that failed.
+	pending := map[string][]string{}
+	for _, u := range urls {
+		if outcomes[u].Result == nil {
+			pending[siteOf(u)] = appendUnique(pending[siteOf(u)], normalizeURL(u))
+		}
+	}
+	candidates := map[string][]*CrawlResult{}
+	for _, result := range unmatched {
+		candidates[siteOf(result.URL)] = append(candidates[siteOf(result.URL)], result)
+	}
+	for site, keys := range pending {
+		if len(keys) == 1 && len(candidates[site]) == 1 {
+			assign(keys[0], candidates[site][0], true)
+		}
+	}
+}
+
+// redirectSources returns the URLs the API reports a result was requested as
+func redirectSources(result *CrawlResult) []string {
+	var sources []string
+	add := func(m map[string]interface{}) {
+		for _, key := range redirectSourceKeys {
+			if s, ok := m[key].(string); ok && s != "" {
+				sources = append(sources, s)
+			}
+		}
+	}
+
+	add(result.Data)
+	if data, err := result.ResultData(); err == nil {
+		add(data.Metadata.Extra)
+	}
+	return sources
+}
+
+// normalizeURL returns a comparison key for rawURL: scheme and host are
+// lowercased, default ports, fragments and trailing slashes are dropped and
+// query parameters are sorted
+func normalizeURL(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	u.Host = host
+	if port != "" {
+		u.Host = host + ":" + port
+	}
+
+	u.Fragment = ""
+	u.RawFragment = ""
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+	if u.RawQuery != "" {
+		u.RawQuery = u.Query().Encode()
+	}
+
+	return u.String()
+}
+
+// siteOf returns the host of rawURL without a "www." prefix
+func siteOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// appendUnique appends s to list unless it is already present
+func appendUnique(list []string, s string) []string {
+	for _, item := range list {
+		if item == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
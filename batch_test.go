@@ -0,0 +1,399 @@
+package watercrawl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"https://example.com", "https://example.com/", true},
+		{"HTTPS://Example.COM/Path", "https://example.com/Path", true},
+		{"https://example.com:443/a", "https://example.com/a", true},
+		{"http://example.com:80/a", "http://example.com/a", true},
+		{"https://example.com:8443/a", "https://example.com/a", false},
+		{"https://example.com/a/", "https://example.com/a", true},
+		{"https://example.com/a#top", "https://example.com/a", true},
+		{"https://example.com/a?b=2&a=1", "https://example.com/a?a=1&b=2", true},
+		{"https://example.com/a", "https://example.com/A", false},
+		{"http://example.com/a", "https://example.com/a", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			if got := normalizeURL(tt.a) == normalizeURL(tt.b); got != tt.same {
+				t.Errorf("normalizeURL(%q) = %q, normalizeURL(%q) = %q, same = %v, want %v",
+					tt.a, normalizeURL(tt.a), tt.b, normalizeURL(tt.b), got, tt.same)
+			}
+		})
+	}
+}
+
+func TestMatchBatchResults(t *testing.T) {
+	tests := []struct {
+		name    string
+		urls    []string
+		crawled []CrawlResult
+		// want maps input URLs to the UUID of their result, "" for none
+		want           map[string]string
+		wantRedirected []string
+	}{
+		{
+			name: "normalized",
+			urls: []string{"https://example.com", "https://Example.com/a/"},
+			crawled: []CrawlResult{
+				{UUID: "r1", URL: "https://example.com/a"},
+				{UUID: "r2", URL: "https://example.com/"},
+			},
+			want: map[string]string{"https://example.com": "r2", "https://Example.com/a/": "r1"},
+		},
+		{
+			name: "redirect source",
+			urls: []string{"https://example.com/old", "https://example.com/other"},
+			crawled: []CrawlResult{
+				{UUID: "r1", URL: "https://example.com/new", Data: map[string]interface{}{"source_url": "https://example.com/old"}},
+			},
+			want:           map[string]string{"https://example.com/old": "r1", "https://example.com/other": ""},
+			wantRedirected: []string{"https://example.com/old"},
+		},
+		{
+			name: "elimination by site",
+			urls: []string{"https://example.com/a", "https://other.com/b"},
+			crawled: []CrawlResult{
+				{UUID: "r1", URL: "https://www.example.com/landing"},
+				{UUID: "r2", URL: "https://other.com/b"},
+			},
+			want:           map[string]string{"https://example.com/a": "r1", "https://other.com/b": "r2"},
+			wantRedirected: []string{"https://example.com/a"},
+		},
+		{
+			name: "no elimination across sites",
+			urls: []string{"https://a.com/x", "https://b.com/y"},
+			crawled: []CrawlResult{
+				{UUID: "r1", URL: "https://a.com/x"},
+				{UUID: "r2", URL: "https://a.com/z"},
+			},
+			want: map[string]string{"https://a.com/x": "r1", "https://b.com/y": ""},
+		},
+		{
+			name: "ambiguous",
+			urls: []string{"https://example.com/a", "https://example.com/b"},
+			crawled: []CrawlResult{
+				{UUID: "r1", URL: "https://example.com/c"},
+				{UUID: "r2", URL: "https://example.com/d"},
+			},
+			want: map[string]string{"https://example.com/a": "", "https://example.com/b": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcomes := map[string]*BatchScrapeResult{}
+			for _, u := range tt.urls {
+				outcomes[u] = &BatchScrapeResult{URL: u}
+			}
+
+			matchBatchResults(outcomes, tt.urls, tt.crawled)
+
+			for u, want := range tt.want {
+				got := ""
+				if outcomes[u].Result != nil {
+					got = outcomes[u].Result.UUID
+				}
+				if got != want {
+					t.Errorf("result of %s = %q, want %q", u, got, want)
+				}
+			}
+			for _, u := range tt.urls {
+				wantRedirected := false
+				for _, r := range tt.wantRedirected {
+					wantRedirected = wantRedirected || r == u
+				}
+				if outcomes[u].Redirected != wantRedirected {
+					t.Errorf("Redirected of %s = %v, want %v", u, outcomes[u].Redirected, wantRedirected)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_BatchScrape(t *testing.T) {
+	var mu sync.Mutex
+	batches := map[string][]string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/api/v1/core/crawl-requests/")
+
+		switch {
+		case path == "" && r.Method == http.MethodPost:
+			var input struct {
+				URL URLs `json:"url"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+				t.Errorf("Failed to decode request body: %v", err)
+			}
+			mu.Lock()
+			id := fmt.Sprintf("batch-%d", len(batches))
+			batches[id] = input.URL
+			mu.Unlock()
+			fmt.Fprintf(w, `{"uuid":%q,"status":"new"}`, id)
+		case strings.HasSuffix(path, "/status/"):
+			id := strings.TrimSuffix(path, "/status/")
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprintf(w, "data: {\"type\":\"state\",\"data\":{\"uuid\":%q,\"status\":\"finished\"}}\n\n", id)
+		case strings.HasSuffix(path, "/results/"):
+			id := strings.TrimSuffix(path, "/results/")
+			mu.Lock()
+			urls := batches[id]
+			mu.Unlock()
+
+			var results []CrawlResult
+			for _, u := range urls {
+				// One page of the batch fails without result
+				if strings.HasSuffix(u, "/missing") {
+					continue
+				}
+				results = append(results, CrawlResult{UUID: "result-" + u, URL: strings.TrimSuffix(u, "/") + "/"})
+			}
+			if err := json.NewEncoder(w).Encode(CrawlResultList{Count: len(results), Results: results}); err != nil {
+				t.Errorf("Failed to encode response: %v", err)
+			}
+		default:
+			id := strings.TrimSuffix(path, "/")
+			fmt.Fprintf(w, `{"uuid":%q,"status":"finished"}`, id)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	urls := []string{
+		"https://example.com/a",
+		"https://example.com/b",
+		"https://example.com/missing",
+		"https://example.com/a",
+		"not a url",
+	}
+	results, err := client.BatchScrape(context.Background(), urls, &BatchScrapeOptions{BatchSize: 2})
+	if err != nil {
+		t.Fatalf("BatchScrape() error = %v", err)
+	}
+
+	if len(batches) != 2 {
+		t.Errorf("crawl requests = %d, want 2", len(batches))
+	}
+	if len(results) != 4 {
+		t.Errorf("BatchScrape() returned %d results, want 4", len(results))
+	}
+
+	for _, u := range []string{"https://example.com/a", "https://example.com/b"} {
+		result := results[u]
+		if result == nil || result.Err != nil || result.Result == nil || result.Result.UUID != "result-"+u {
+			t.Errorf("BatchScrape()[%q] = %+v, want result-%s", u, result, u)
+			continue
+		}
+		if result.CrawlRequest == "" || result.Redirected {
+			t.Errorf("BatchScrape()[%q] = %+v, want crawl request and no redirect", u, result)
+		}
+	}
+
+	if err := results["https://example.com/missing"].Err; !errors.Is(err, ErrNoResult) {
+		t.Errorf("BatchScrape() missing URL error = %v, want %v", err, ErrNoResult)
+	}
+
+	var validationErr *ValidationError
+	if err := results["not a url"].Err; !errors.As(err, &validationErr) {
+		t.Errorf("BatchScrape() invalid URL error = %v, want *ValidationError", err)
+	}
+}
+
+func TestClient_BatchScrape_SpiderOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		spiderOptions *SpiderOptions
+		want          string
+	}{
+		{
+			name: "defaults",
+			want: `{"max_depth":0,"page_limit":2,"allowed_domains":["*"]}`,
+		},
+		{
+			name:          "overrides",
+			spiderOptions: &SpiderOptions{PageLimit: 10, ProxyServer: "proxy"},
+			want:          `{"max_depth":0,"page_limit":10,"allowed_domains":["*"],"proxy_server":"proxy"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got json.RawMessage
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var input struct {
+					Options struct {
+						SpiderOptions json.RawMessage `json:"spider_options"`
+					} `json:"options"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+					t.Errorf("Failed to decode request body: %v", err)
+				}
+				got = input.Options.SpiderOptions
+				w.WriteHeader(http.StatusPaymentRequired)
+			}))
+			defer server.Close()
+
+			client := NewClient("test-key", server.URL+"/")
+
+			urls := []string{"https://example.com/a", "https://example.com/b"}
+			if _, err := client.BatchScrape(context.Background(), urls, &BatchScrapeOptions{SpiderOptions: tt.spiderOptions}); err != nil {
+				t.Fatalf("BatchScrape() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("spider_options = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_BatchScrape_Concurrency(t *testing.T) {
+	var mu sync.Mutex
+	var created, active, maxActive int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/api/v1/core/crawl-requests/")
+
+		switch {
+		case path == "" && r.Method == http.MethodPost:
+			mu.Lock()
+			created++
+			id := fmt.Sprintf("batch-%d", created)
+			mu.Unlock()
+			fmt.Fprintf(w, `{"uuid":%q,"status":"new"}`, id)
+		case strings.HasSuffix(path, "/status/"):
+			mu.Lock()
+			active++
+			if active > maxActive {
+				maxActive = active
+			}
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			mu.Lock()
+			active--
+			mu.Unlock()
+
+			id := strings.TrimSuffix(path, "/status/")
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprintf(w, "data: {\"type\":\"state\",\"data\":{\"uuid\":%q,\"status\":\"finished\"}}\n\n", id)
+		case strings.HasSuffix(path, "/results/"):
+			fmt.Fprint(w, `{"count":0,"results":[]}`)
+		default:
+			fmt.Fprintf(w, `{"uuid":%q,"status":"finished"}`, strings.TrimSuffix(path, "/"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	var urls []string
+	for i := 0; i < 6; i++ {
+		urls = append(urls, fmt.Sprintf("https://example.com/%d", i))
+	}
+	if _, err := client.BatchScrape(context.Background(), urls, &BatchScrapeOptions{BatchSize: 1, Concurrency: 2}); err != nil {
+		t.Fatalf("BatchScrape() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if created != 6 {
+		t.Errorf("crawl requests = %d, want 6", created)
+	}
+	if maxActive > 2 {
+		t.Errorf("crawl requests in flight = %d, want at most 2", maxActive)
+	}
+}
+
+func TestClient_BatchScrape_ContextDone(t *testing.T) {
+	var mu sync.Mutex
+	var creates int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/api/v1/core/crawl-requests/")
+
+		switch {
+		case path == "" && r.Method == http.MethodPost:
+			fmt.Fprint(w, `{"uuid":"batch-1","status":"new"}`)
+		case strings.HasSuffix(path, "/status/"):
+			// The crawl never ends
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if r.Method == http.MethodPost {
+				mu.Lock()
+				creates++
+				mu.Unlock()
+			}
+			return http.DefaultTransport.RoundTrip(r)
+		}),
+	}
+	client := NewClient("test-key", server.URL+"/", WithHTTPClient(httpClient))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	urls := []string{"https://example.com/a", "https://example.com/b", "https://example.com/c"}
+	results, err := client.BatchScrape(ctx, urls, &BatchScrapeOptions{BatchSize: 1, Concurrency: 1})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("BatchScrape() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if creates != 1 {
+		t.Errorf("crawl requests submitted = %d, want 1", creates)
+	}
+	for _, u := range urls[1:] {
+		if results[u].Err != context.DeadlineExceeded {
+			t.Errorf("result of %s error = %v, want %v", u, results[u].Err, context.DeadlineExceeded)
+		}
+	}
+}
+
+func TestClient_BatchScrape_CreateError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusPaymentRequired)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	results, err := client.BatchScrape(context.Background(), []string{"https://example.com/a", "https://example.com/b"}, nil)
+	if err != nil {
+		t.Fatalf("BatchScrape() error = %v", err)
+	}
+	for u, result := range results {
+		if !errors.Is(result.Err, ErrQuotaExceeded) {
+			t.Errorf("BatchScrape()[%q].Err = %v, want %v", u, result.Err, ErrQuotaExceeded)
+		}
+	}
+}
@@ -8,86 +8,282 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBaseURL is the base URL of the hosted WaterCrawl API
+	DefaultBaseURL = "https://app.watercrawl.dev/"
+
+	// DefaultUserAgent is the User-Agent header sent when none is configured
+	DefaultUserAgent = "WaterCrawl-Go-SDK"
+
+	// DefaultAPIVersion is the API version segment used in request paths
+	DefaultAPIVersion = "v1"
+
+	defaultAcceptLanguage = "en-US"
 )
 
 // Client represents the WaterCrawl API client
 type Client struct {
-	apiKey     string
-	baseURL    string
-	httpClient *http.Client
-	version    string
+	apiKey          string
+	baseURL         string
+	base            *url.URL
+	baseErr         error
+	apiVersion      string
+	httpClient      *http.Client
+	version         string
+	timeout         time.Duration
+	userAgent       string
+	acceptLanguage  string
+	headers         http.Header
+	logger          Logger
+	retryPolicy     RetryPolicy
+	reconnectPolicy ReconnectPolicy
+	rateLimiter     *tokenBucket
+	concurrency     chan struct{}
+
+	keepAbandonedCrawls bool
+
+	rateLimitMu        sync.Mutex
+	rateLimit          *RateLimitInfo
+	rateLimitThreshold int
+	rateLimitCallback  func(RateLimitInfo)
+}
+
+// New creates a new WaterCrawl API client, returning an error if the
+// configured base URL is invalid. Use WithBaseURL to target a self-hosted deployment.
+func New(apiKey string, opts ...Option) (*Client, error) {
+	c := NewClient(apiKey, "", opts...)
+	if c.baseErr != nil {
+		return nil, c.baseErr
+	}
+	return c, nil
 }
 
-// NewClient creates a new WaterCrawl API client
-func NewClient(apiKey string, baseURL string) *Client {
+// NewClient creates a new WaterCrawl API client.
+// An empty baseURL uses DefaultBaseURL. Options are applied in order and may
+// override the base URL passed as argument. An invalid base URL is reported
+// by every request; use New to detect it up front.
+func NewClient(apiKey string, baseURL string, opts ...Option) *Client {
 
 	if baseURL == "" {
-		baseURL = "https://app.watercrawl.dev/"
+		baseURL = DefaultBaseURL
+	}
+
+	c := &Client{
+		apiKey:          apiKey,
+		baseURL:         baseURL,
+		apiVersion:      DefaultAPIVersion,
+		httpClient:      &http.Client{},
+		version:         Version,
+		userAgent:       DefaultUserAgent,
+		acceptLanguage:  defaultAcceptLanguage,
+		headers:         make(http.Header),
+		logger:          nopLogger{},
+		reconnectPolicy: DefaultReconnectPolicy(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.base, c.baseErr = parseBaseURL(c.baseURL)
+
+	return c
+}
+
+// send sends req, waiting at most the client's timeout for the response
+// headers. Reading the body is not bounded by the timeout, so event streams
+// and downloads can outlast it; they end with the request context.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if c.timeout <= 0 {
+		return c.httpClient.Do(req)
 	}
 
-	return &Client{
-		apiKey:     apiKey,
-		baseURL:    baseURL,
-		httpClient: &http.Client{},
-		version:    Version,
+	ctx, cancel := context.WithCancel(req.Context())
+	timer := time.AfterFunc(c.timeout, cancel)
+	resp, err := c.httpClient.Do(req.WithContext(ctx))
+	if !timer.Stop() {
+		// The timer fired, possibly just after the headers arrived
+		cancel()
+		if err == nil {
+			c.closeBody(resp)
+		}
+		return nil, &TimeoutError{
+			Operation: req.Method + " " + req.URL.Path,
+			Message:   fmt.Sprintf("no response within %s", c.timeout),
+			Err:       context.DeadlineExceeded,
+		}
 	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: cancel}
+	return resp, nil
 }
 
 // doRequest performs an HTTP request and returns the response
 func (c *Client) doRequest(ctx context.Context, method, endpoint string, queryParams url.Values, body interface{}) (*http.Response, error) {
+	return c.doRequestWithHeader(ctx, method, endpoint, queryParams, body, nil)
+}
+
+// doRequestWithHeader performs an HTTP request with additional request headers.
+// Failed attempts are retried according to the client's retry policy.
+func (c *Client) doRequestWithHeader(ctx context.Context, method, endpoint string, queryParams url.Values, body interface{}, header http.Header) (*http.Response, error) {
 	// Construct the full URL
-	u, err := url.Parse(c.baseURL)
-	if err != nil {
-		return nil, fmt.Errorf("invalid base URL: %w", err)
+	if c.baseErr != nil {
+		return nil, c.baseErr
 	}
-	u.Path = endpoint
+	u := *c.base
+	u.Path = joinURLPath(c.base.Path, endpoint)
+	u.RawPath = ""
+	u.RawQuery = ""
 	if queryParams != nil {
 		u.RawQuery = queryParams.Encode()
 	}
 
-	// Prepare request body if provided
-	var bodyReader io.Reader
+	// Marshal the request body once so it can be replayed on retries
+	var bodyBytes []byte
 	if body != nil {
-		bodyBytes, err := json.Marshal(body)
+		marshaled, err := json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		bodyReader = bytes.NewReader(bodyBytes)
+		bodyBytes = marshaled
 	}
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, method, u.String(), bodyReader)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+	retryable := c.retryPolicy.MaxAttempts > 1 && isIdempotent(method, header)
 
-	// Set headers
-	req.Header.Set("X-API-Key", c.apiKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "WaterCrawl-Go-SDK")
-	req.Header.Set("Accept-Language", "en-US")
+	for attempt := 1; ; attempt++ {
+		var bodyReader io.Reader
+		if bodyBytes != nil {
+			bodyReader = bytes.NewReader(bodyBytes)
+		}
 
-	// For debugging
-	fmt.Printf("Making request to: %s %s\n", method, u.String())
+		// Create request
+		req, err := http.NewRequestWithContext(ctx, method, u.String(), bodyReader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
 
-	// Execute request
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		// Set headers, custom ones first so the managed headers always win
+		for key, values := range c.headers {
+			for _, value := range values {
+				req.Header.Add(key, value)
+			}
+		}
+		for key, values := range header {
+			req.Header[key] = append([]string(nil), values...)
+		}
+		req.Header.Set("X-API-Key", c.apiKey)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("User-Agent", c.userAgent)
+		req.Header.Set("Accept-Language", c.acceptLanguage)
+
+		// Never log headers here, they carry the API key
+		c.logger.Debugf("Making request to: %s %s (attempt %d)", method, u.String(), attempt)
+
+		// Wait for the rate limiter and a concurrency slot
+		release, err := c.acquire(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("request failed: %w", err)
+		}
+
+		// Execute request
+		resp, err := c.send(req)
+		if err != nil {
+			release()
+			if !retryable || attempt >= c.retryPolicy.MaxAttempts || ctx.Err() != nil || !c.retryPolicy.RetryNetworkErrors {
+				return nil, fmt.Errorf("request failed: %w", err)
+			}
+			delay := c.retryPolicy.backoff(attempt)
+			c.logger.Warnf("Request failed, retrying in %s: %v", delay, err)
+			if err := sleepContext(ctx, delay); err != nil {
+				return nil, fmt.Errorf("request failed: %w", err)
+			}
+			continue
+		}
+
+		if isEventStream(resp) {
+			// Event streams stay open for as long as they are monitored and
+			// would starve the requests made meanwhile, they only count
+			// against the concurrency limit until their headers are received
+			release()
+		} else {
+			resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+		}
+
+		// Log response status
+		c.logger.Debugf("Received response: %d %s", resp.StatusCode, resp.Status)
+		c.recordRateLimit(resp)
+
+		if !retryable || attempt >= c.retryPolicy.MaxAttempts || !c.retryPolicy.retryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+
+		delay := c.retryPolicy.backoff(attempt)
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			if c.retryPolicy.MaxBackoff > 0 && retryAfter > c.retryPolicy.MaxBackoff {
+				// Retrying sooner than requested would fail again, leave it to the caller
+				c.logger.Warnf("Received status %d with Retry-After %s beyond the maximum backoff, not retrying", resp.StatusCode, retryAfter)
+				return resp, nil
+			}
+			delay = retryAfter
+		}
+
+		// Drain the body so the connection can be reused
+		_, _ = io.Copy(io.Discard, resp.Body)
+		if err := resp.Body.Close(); err != nil {
+			c.logger.Warnf("Error closing response body: %v", err)
+		}
+
+		c.logger.Warnf("Received status %d, retrying in %s", resp.StatusCode, delay)
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, fmt.Errorf("request failed: %w", err)
+		}
 	}
+}
 
-	// Log response status for debugging
-	fmt.Printf("Received response: %d %s\n", resp.StatusCode, resp.Status)
+// apiPath returns the path of an API endpoint for the configured API version
+func (c *Client) apiPath(format string, args ...interface{}) string {
+	return "/api/" + c.apiVersion + "/" + fmt.Sprintf(format, args...)
+}
 
-	return resp, nil
+// parseBaseURL parses and validates the base URL of the API
+func parseBaseURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, &ValidationError{
+			Field:   "baseURL",
+			Message: fmt.Sprintf("invalid base URL: %v", err),
+		}
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, &ValidationError{
+			Field:   "baseURL",
+			Message: fmt.Sprintf("base URL %q must be an absolute http or https URL", raw),
+		}
+	}
+	return u, nil
+}
+
+// joinURLPath joins the base URL path and an endpoint with exactly one slash
+// between them, keeping the trailing slash of the endpoint
+func joinURLPath(basePath, endpoint string) string {
+	return strings.TrimSuffix(basePath, "/") + "/" + strings.TrimPrefix(endpoint, "/")
 }
 
 // processResponse processes the HTTP response and unmarshals the response body
 func (c *Client) processResponse(resp *http.Response, v interface{}) error {
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
-			fmt.Printf("Error closing response body: %v\n", err)
+			c.logger.Warnf("Error closing response body: %v", err)
 		}
 	}()
 
@@ -101,27 +297,7 @@ func (c *Client) processResponse(resp *http.Response, v interface{}) error {
 	}
 
 	if resp.StatusCode >= 400 {
-		// Try to parse error response as JSON
-		var apiErr struct {
-			Error string `json:"error"`
-		}
-		if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Error != "" {
-			return &APIError{
-				StatusCode: resp.StatusCode,
-				Message:    apiErr.Error,
-			}
-		}
-
-		// If JSON parsing fails or no error message, use raw body or default message
-		errorMsg := string(body)
-		if errorMsg == "" {
-			errorMsg = fmt.Sprintf("HTTP error %d", resp.StatusCode)
-		}
-
-		return &APIError{
-			StatusCode: resp.StatusCode,
-			Message:    errorMsg,
-		}
+		return c.errorFromResponse(resp, body)
 	}
 
 	if v != nil {
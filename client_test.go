@@ -139,4 +139,68 @@ func TestClient_processResponse(t *testing.T) {
 			}
 		})
 	}
-} 
\ No newline at end of file
+}
+
+func TestNew_InvalidBaseURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+	}{
+		{name: "relative URL", baseURL: "internal.example.com/watercrawl"},
+		{name: "unsupported scheme", baseURL: "ftp://internal.example.com/"},
+		{name: "unparsable URL", baseURL: "http://[::1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New("test-key", WithBaseURL(tt.baseURL)); err == nil {
+				t.Errorf("New() with base URL %q error = nil, want error", tt.baseURL)
+			}
+
+			// NewClient defers the error to the first request
+			client := NewClient("test-key", tt.baseURL)
+			if _, err := client.doRequest(context.Background(), http.MethodGet, "/test", nil, nil); err == nil {
+				t.Errorf("doRequest() with base URL %q error = nil, want error", tt.baseURL)
+			}
+		})
+	}
+}
+
+func TestClient_doRequest_BasePath(t *testing.T) {
+	tests := []struct {
+		name       string
+		basePath   string
+		apiVersion string
+		wantPath   string
+	}{
+		{name: "no base path", basePath: "", wantPath: "/api/v1/core/crawl-requests/"},
+		{name: "root base path", basePath: "/", wantPath: "/api/v1/core/crawl-requests/"},
+		{name: "base path with trailing slash", basePath: "/watercrawl/", wantPath: "/watercrawl/api/v1/core/crawl-requests/"},
+		{name: "base path without trailing slash", basePath: "/watercrawl", wantPath: "/watercrawl/api/v1/core/crawl-requests/"},
+		{name: "API version override", basePath: "/watercrawl/", apiVersion: "v2", wantPath: "/watercrawl/api/v2/core/crawl-requests/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"count":0,"results":[]}`))
+			}))
+			defer server.Close()
+
+			client, err := New("test-key", WithBaseURL(server.URL+tt.basePath), WithAPIVersion(tt.apiVersion))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			if _, err := client.GetCrawlRequests(context.Background(), 1, 10); err != nil {
+				t.Fatalf("GetCrawlRequests() error = %v", err)
+			}
+			if gotPath != tt.wantPath {
+				t.Errorf("request path = %v, want %v", gotPath, tt.wantPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package watercrawl
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// SpiderOptions controls which pages a crawl visits
+type SpiderOptions struct {
+	// MaxDepth is the maximum link depth followed from the start URL
+	MaxDepth *int `json:"max_depth,omitempty"`
+	// PageLimit is the maximum number of pages crawled
+	PageLimit int `json:"page_limit,omitempty"`
+	// AllowedDomains restricts the crawl to these domains; "*" allows any domain
+	AllowedDomains []string `json:"allowed_domains,omitempty"`
+	// ExcludePaths lists path patterns that are never crawled
+	ExcludePaths []string `json:"exclude_paths,omitempty"`
+	// IncludePaths lists path patterns that are crawled exclusively
+	IncludePaths []string `json:"include_paths,omitempty"`
+	// ProxyServer selects a proxy server configured on the account
+	ProxyServer string `json:"proxy_server,omitempty"`
+
+	// Extra holds options not covered by the fields above.
+	// Typed fields take precedence over Extra keys of the same name.
+	Extra map[string]interface{} `json:"-"`
+}
+
+// PageOptions controls how each page is loaded and what content is extracted
+type PageOptions struct {
+	// ExcludeTags lists HTML tags removed from the result
+	ExcludeTags []string `json:"exclude_tags,omitempty"`
+	// IncludeTags lists HTML tags kept in the result; everything else is removed
+	IncludeTags []string `json:"include_tags,omitempty"`
+	// WaitTime is the time in milliseconds to wait after the page loads
+	WaitTime *int `json:"wait_time,omitempty"`
+	// OnlyMainContent strips headers, footers and navigation
+	OnlyMainContent *bool `json:"only_main_content,omitempty"`
+	// IncludeHTML adds the page HTML to the result
+	IncludeHTML *bool `json:"include_html,omitempty"`
+	// IncludeLinks adds the links found on the page to the result
+	IncludeLinks *bool `json:"include_links,omitempty"`
+	// Timeout is the page load timeout in milliseconds
+	Timeout int `json:"timeout,omitempty"`
+	// AcceptCookiesSelector is a CSS selector clicked to dismiss cookie banners
+	AcceptCookiesSelector string `json:"accept_cookies_selector,omitempty"`
+	// Locale is the browser locale, e.g. "en-US"
+	Locale string `json:"locale,omitempty"`
+	// ExtraHeaders are sent with every page request
+	ExtraHeaders map[string]string `json:"extra_headers,omitempty"`
+	// Actions are performed on the page after it loads
+	Actions []PageAction `json:"actions,omitempty"`
+
+	// Extra holds options not covered by the fields above.
+	// Typed fields take precedence over Extra keys of the same name.
+	Extra map[string]interface{} `json:"-"`
+}
+
+// PageAction is an action performed on a page, such as taking a screenshot
+type PageAction struct {
+	Type string `json:"type"`
+}
+
+// Page action types supported by the API
+const (
+	PageActionScreenshot = "screenshot"
+	PageActionPDF        = "pdf"
+)
+
+// PluginOptions configures the plugins run on each crawled page
+type PluginOptions struct {
+	// OpenAIExtract extracts structured data from pages with an OpenAI model
+	OpenAIExtract *OpenAIExtractOptions `json:"openai_extract,omitempty"`
+
+	// Extra holds the options of plugins not covered above, keyed by plugin name.
+	// Typed fields take precedence over Extra keys of the same name.
+	Extra map[string]interface{} `json:"-"`
+}
+
+// OpenAIExtractOptions configures the openai_extract plugin
+type OpenAIExtractOptions struct {
+	// IsActive enables the plugin
+	IsActive bool `json:"is_active"`
+	// LLMModel is the model used for extraction, e.g. "gpt-4o-mini"
+	LLMModel string `json:"llm_model,omitempty"`
+	// ExtractorSchema is the JSON schema of the extracted data
+	ExtractorSchema map[string]interface{} `json:"extractor_schema,omitempty"`
+	// Prompt instructs the model what to extract
+	Prompt string `json:"prompt,omitempty"`
+
+	// Extra holds options not covered by the fields above.
+	// Typed fields take precedence over Extra keys of the same name.
+	Extra map[string]interface{} `json:"-"`
+}
+
+// Bool returns a pointer to b, for optional boolean options
+func Bool(b bool) *bool {
+	return &b
+}
+
+// Int returns a pointer to i, for optional integer options
+func Int(i int) *int {
+	return &i
+}
+
+// MarshalJSON implements json.Marshaler, merging Extra into the output
+func (o SpiderOptions) MarshalJSON() ([]byte, error) {
+	type spiderOptions SpiderOptions
+	return marshalWithExtra(spiderOptions(o), o.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, collecting unknown keys into Extra
+func (o *SpiderOptions) UnmarshalJSON(data []byte) error {
+	type spiderOptions SpiderOptions
+	var v spiderOptions
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*o = SpiderOptions(v)
+	o.Extra = extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, merging Extra into the output
+func (o PageOptions) MarshalJSON() ([]byte, error) {
+	type pageOptions PageOptions
+	return marshalWithExtra(pageOptions(o), o.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, collecting unknown keys into Extra
+func (o *PageOptions) UnmarshalJSON(data []byte) error {
+	type pageOptions PageOptions
+	var v pageOptions
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*o = PageOptions(v)
+	o.Extra = extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, merging Extra into the output
+func (o PluginOptions) MarshalJSON() ([]byte, error) {
+	type pluginOptions PluginOptions
+	return marshalWithExtra(pluginOptions(o), o.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, collecting unknown plugins into Extra
+func (o *PluginOptions) UnmarshalJSON(data []byte) error {
+	type pluginOptions PluginOptions
+	var v pluginOptions
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*o = PluginOptions(v)
+	o.Extra = extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, merging Extra into the output
+func (o OpenAIExtractOptions) MarshalJSON() ([]byte, error) {
+	type openAIExtractOptions OpenAIExtractOptions
+	return marshalWithExtra(openAIExtractOptions(o), o.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, collecting unknown keys into Extra
+func (o *OpenAIExtractOptions) UnmarshalJSON(data []byte) error {
+	type openAIExtractOptions OpenAIExtractOptions
+	var v openAIExtractOptions
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*o = OpenAIExtractOptions(v)
+	o.Extra = extra
+	return nil
+}
+
+// marshalWithExtra marshals v as a JSON object and adds the extra keys it does not already define
+func marshalWithExtra(v interface{}, extra map[string]interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	for key, value := range extra {
+		if _, ok := fields[key]; ok {
+			continue
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		fields[key] = raw
+	}
+
+	return json.Marshal(fields)
+}
+
+// unmarshalWithExtra unmarshals data into v and returns the keys v has no field for
+func unmarshalWithExtra(data []byte, v interface{}) (map[string]interface{}, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	known := jsonFieldNames(reflect.TypeOf(v).Elem())
+	var extra map[string]interface{}
+	for key, value := range fields {
+		if known[key] {
+			continue
+		}
+		if extra == nil {
+			extra = make(map[string]interface{})
+		}
+		extra[key] = value
+	}
+
+	return extra, nil
+}
+
+// jsonFieldNames returns the JSON keys of the exported fields of struct type t
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		names[name] = true
+	}
+	return names
+}
@@ -0,0 +1,127 @@
+package watercrawl
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPageOptions_MarshalJSON(t *testing.T) {
+	options := PageOptions{
+		OnlyMainContent: Bool(false),
+		WaitTime:        Int(0),
+		Actions:         []PageAction{{Type: PageActionScreenshot}},
+		Extra: map[string]interface{}{
+			"custom_option":     "value",
+			"only_main_content": true,
+		},
+	}
+
+	data, err := json.Marshal(options)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	want := map[string]interface{}{
+		"only_main_content": false,
+		"wait_time":         float64(0),
+		"actions":           []interface{}{map[string]interface{}{"type": "screenshot"}},
+		"custom_option":     "value",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("json.Marshal() = %s, want %v", data, want)
+	}
+}
+
+func TestSpiderOptions_UnmarshalJSON(t *testing.T) {
+	data := []byte(`{"max_depth":2,"allowed_domains":["example.com"],"future_option":{"enabled":true}}`)
+
+	var options SpiderOptions
+	if err := json.Unmarshal(data, &options); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if options.MaxDepth == nil || *options.MaxDepth != 2 {
+		t.Errorf("MaxDepth = %v, want 2", options.MaxDepth)
+	}
+	if !reflect.DeepEqual(options.AllowedDomains, []string{"example.com"}) {
+		t.Errorf("AllowedDomains = %v, want [example.com]", options.AllowedDomains)
+	}
+	if len(options.Extra) != 1 || options.Extra["future_option"] == nil {
+		t.Errorf("Extra = %v, want only future_option", options.Extra)
+	}
+
+	// Unknown keys survive a round trip
+	roundTrip, err := json.Marshal(options)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var got, want map[string]interface{}
+	if err := json.Unmarshal(roundTrip, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip = %s, want %s", roundTrip, data)
+	}
+}
+
+func TestPluginOptions_UnmarshalJSON(t *testing.T) {
+	data := []byte(`{"openai_extract":{"is_active":true,"llm_model":"gpt-4o-mini","temperature":0.5},"future_plugin":{"is_active":false}}`)
+
+	var options PluginOptions
+	if err := json.Unmarshal(data, &options); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	extract := options.OpenAIExtract
+	if extract == nil || !extract.IsActive || extract.LLMModel != "gpt-4o-mini" {
+		t.Fatalf("OpenAIExtract = %+v, want active with llm_model gpt-4o-mini", extract)
+	}
+	if len(extract.Extra) != 1 || extract.Extra["temperature"] != 0.5 {
+		t.Errorf("OpenAIExtract.Extra = %v, want only temperature", extract.Extra)
+	}
+	if len(options.Extra) != 1 || options.Extra["future_plugin"] == nil {
+		t.Errorf("Extra = %v, want only future_plugin", options.Extra)
+	}
+
+	// Unknown plugins and options survive a round trip
+	roundTrip, err := json.Marshal(options)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var got, want map[string]interface{}
+	if err := json.Unmarshal(roundTrip, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip = %s, want %s", roundTrip, data)
+	}
+}
+
+func TestCrawlOptions_MarshalJSON(t *testing.T) {
+	options := CrawlOptions{
+		SpiderOptions: SpiderOptions{PageLimit: 10},
+		PluginOptions: PluginOptions{OpenAIExtract: &OpenAIExtractOptions{IsActive: true}},
+	}
+
+	data, err := json.Marshal(options)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	want := `{"spider_options":{"page_limit":10},"page_options":{},"plugin_options":{"openai_extract":{"is_active":true}}}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+}
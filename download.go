@@ -0,0 +1,344 @@
+package watercrawl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// partSuffix is appended to the path of a file download while it is in progress
+const partSuffix = ".part"
+
+// validatorSuffix is appended to the path of a partial download for the file
+// holding the ETag or Last-Modified value of the document being downloaded
+const validatorSuffix = ".validator"
+
+// ResultDecoder decodes crawl results one at a time from a download, without
+// holding the whole response in memory. It accepts a JSON array of results
+// and an object holding the array under "results".
+type ResultDecoder struct {
+	dec     *json.Decoder
+	started bool
+	done    bool
+}
+
+// NewResultDecoder creates a decoder reading from r
+func NewResultDecoder(r io.Reader) *ResultDecoder {
+	return &ResultDecoder{dec: json.NewDecoder(r)}
+}
+
+// Next returns the next result. It returns io.EOF once all results have been decoded.
+func (d *ResultDecoder) Next() (*CrawlResult, error) {
+	if d.done {
+		return nil, io.EOF
+	}
+	if !d.started {
+		if err := d.start(); err != nil {
+			return nil, err
+		}
+		d.started = true
+		if d.done {
+			return nil, io.EOF
+		}
+	}
+
+	if !d.dec.More() {
+		// Consume the closing bracket
+		if _, err := d.dec.Token(); err != nil {
+			return nil, fmt.Errorf("failed to decode results: %w", err)
+		}
+		d.done = true
+		return nil, io.EOF
+	}
+
+	var result CrawlResult
+	if err := d.dec.Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	return &result, nil
+}
+
+// start positions the decoder on the first element of the results array
+func (d *ResultDecoder) start() error {
+	tok, err := d.dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to decode results: %w", unexpectedEOF(err))
+	}
+
+	switch tok {
+	case json.Delim('['):
+		return nil
+	case json.Delim('{'):
+	default:
+		return fmt.Errorf("failed to decode results: unexpected %v", tok)
+	}
+
+	// Skip to the "results" key of the wrapping object
+	for d.dec.More() {
+		key, err := d.dec.Token()
+		if err != nil {
+			return fmt.Errorf("failed to decode results: %w", unexpectedEOF(err))
+		}
+		if key != "results" {
+			var skip json.RawMessage
+			if err := d.dec.Decode(&skip); err != nil {
+				return fmt.Errorf("failed to decode results: %w", err)
+			}
+			continue
+		}
+
+		tok, err := d.dec.Token()
+		if err != nil {
+			return fmt.Errorf("failed to decode results: %w", unexpectedEOF(err))
+		}
+		switch tok {
+		case json.Delim('['):
+			return nil
+		case nil:
+			d.done = true
+			return nil
+		default:
+			return fmt.Errorf("failed to decode results: unexpected %v for results", tok)
+		}
+	}
+
+	// An object without results holds none
+	d.done = true
+	return nil
+}
+
+// unexpectedEOF turns io.EOF into io.ErrUnexpectedEOF, since a truncated
+// document must not look like the end of the results
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// ResultStream is a ResultDecoder over a download response. It must be closed.
+type ResultStream struct {
+	*ResultDecoder
+	body io.Closer
+}
+
+// Close closes the underlying response body
+func (s *ResultStream) Close() error {
+	return s.body.Close()
+}
+
+// StreamCrawlResults downloads the results of a crawl request and decodes
+// them one at a time as they are received
+func (c *Client) StreamCrawlResults(ctx context.Context, id string) (*ResultStream, error) {
+	resp, err := c.openDownload(ctx, id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResultStream{ResultDecoder: NewResultDecoder(resp.Body), body: resp.Body}, nil
+}
+
+// DownloadCrawlRequestTo streams the results of a crawl request to w as
+// received, and returns the number of bytes written
+func (c *Client) DownloadCrawlRequestTo(ctx context.Context, id string, w io.Writer) (int64, error) {
+	resp, err := c.openDownload(ctx, id, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer c.closeBody(resp)
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("failed to download results: %w", err)
+	}
+	return n, nil
+}
+
+// DownloadCrawlRequestToFile downloads the results of a crawl request to path.
+// Data is written to path with a ".part" suffix and renamed once complete, so
+// path never holds a partial download. A ".part" file left by an interrupted
+// download is resumed with a Range request when the server supports it and
+// the document has not changed since, as checked with If-Range against the
+// ETag or Last-Modified value saved next to it; otherwise the download
+// starts over. It returns the size of the downloaded file.
+func (c *Client) DownloadCrawlRequestToFile(ctx context.Context, id, path string) (int64, error) {
+	partPath := path + partSuffix
+	validatorPath := partPath + validatorSuffix
+
+	file, err := os.OpenFile(partPath, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open %s: %w", partPath, err)
+	}
+	defer func() {
+		// Closing twice after a successful download is harmless
+		_ = file.Close()
+	}()
+
+	offset, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, fmt.Errorf("failed to seek %s: %w", partPath, err)
+	}
+
+	var validator string
+	if offset > 0 {
+		// Without a validator there is no telling whether the partial file
+		// belongs to the current document
+		if data, err := os.ReadFile(validatorPath); err == nil {
+			validator = strings.TrimSpace(string(data))
+		}
+		if validator == "" {
+			c.logger.Infof("Partial download of %s has no validator, restarting", id)
+			offset = 0
+		}
+	}
+
+	resp, err := c.openRangeDownload(ctx, id, offset, validator)
+	if err != nil {
+		return 0, err
+	}
+	defer c.closeBody(resp)
+
+	if resp.StatusCode != http.StatusPartialContent {
+		// The server sent the whole document, start over
+		if offset > 0 {
+			c.logger.Infof("Server ignored the range request, restarting download of %s", id)
+		}
+		if err := file.Truncate(0); err != nil {
+			return 0, fmt.Errorf("failed to truncate %s: %w", partPath, err)
+		}
+		if offset, err = file.Seek(0, io.SeekStart); err != nil {
+			return 0, fmt.Errorf("failed to seek %s: %w", partPath, err)
+		}
+	} else {
+		c.logger.Infof("Resuming download of %s at byte %d", id, offset)
+	}
+
+	if err := saveValidator(validatorPath, responseValidator(resp)); err != nil {
+		return 0, err
+	}
+
+	n, err := io.Copy(file, resp.Body)
+	if err != nil {
+		// Keep the partial file so the next call resumes it
+		return 0, fmt.Errorf("failed to download results: %w", err)
+	}
+
+	if err := file.Sync(); err != nil {
+		return 0, fmt.Errorf("failed to sync %s: %w", partPath, err)
+	}
+	if err := file.Close(); err != nil {
+		return 0, fmt.Errorf("failed to close %s: %w", partPath, err)
+	}
+	if err := os.Rename(partPath, path); err != nil {
+		return 0, fmt.Errorf("failed to rename %s: %w", partPath, err)
+	}
+	if err := saveValidator(validatorPath, ""); err != nil {
+		c.logger.Warnf("%v", err)
+	}
+
+	return offset + n, nil
+}
+
+// responseValidator returns the value identifying the version of a
+// downloaded document for If-Range: its strong ETag, or else its
+// Last-Modified date. It returns "" if the response has neither.
+func responseValidator(resp *http.Response) string {
+	if etag := resp.Header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return resp.Header.Get("Last-Modified")
+}
+
+// saveValidator writes validator to path, or removes path if validator is empty
+func saveValidator(path, validator string) error {
+	if validator == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(validator), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// openRangeDownload opens the download of a crawl request from offset, if
+// the document still matches validator. The response is 206 Partial Content
+// if the server honored the range, or the whole document otherwise.
+func (c *Client) openRangeDownload(ctx context.Context, id string, offset int64, validator string) (*http.Response, error) {
+	if offset == 0 {
+		return c.openDownload(ctx, id, nil)
+	}
+
+	header := http.Header{}
+	header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+	header.Set("If-Range", validator)
+
+	resp, err := c.openDownload(ctx, id, header)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		// The partial file does not match the document, start over
+		c.logger.Warnf("Partial download of %s does not match, restarting", id)
+		return c.openDownload(ctx, id, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusPartialContent && contentRangeStart(resp.Header.Get("Content-Range")) != offset {
+		c.closeBody(resp)
+		c.logger.Warnf("Server returned an unexpected range for %s, restarting", id)
+		return c.openDownload(ctx, id, nil)
+	}
+
+	return resp, nil
+}
+
+// openDownload requests the results of a crawl request, returning an error
+// for a failed status
+func (c *Client) openDownload(ctx context.Context, id string, header http.Header) (*http.Response, error) {
+	resp, err := c.doRequestWithHeader(ctx, http.MethodGet, c.apiPath("core/crawl-requests/%s/download/", id), nil, nil, header)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.processResponse(resp, nil)
+	}
+
+	return resp, nil
+}
+
+// closeBody closes a response body, logging failures
+func (c *Client) closeBody(resp *http.Response) {
+	if err := resp.Body.Close(); err != nil {
+		c.logger.Warnf("Error closing response body: %v", err)
+	}
+}
+
+// contentRangeStart returns the first byte position of a Content-Range
+// header ("bytes 100-199/200"), or -1 if it cannot be parsed
+func contentRangeStart(value string) int64 {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "bytes ") {
+		return -1
+	}
+	value = strings.TrimPrefix(value, "bytes ")
+
+	dash := strings.IndexByte(value, '-')
+	if dash < 0 {
+		return -1
+	}
+	start, err := strconv.ParseInt(value[:dash], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return start
+}
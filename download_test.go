@@ -0,0 +1,344 @@
+package watercrawl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testDownload = `[{"uuid":"r1","url":"https://example.com/1"},{"uuid":"r2","url":"https://example.com/2"}]`
+
+// decodeResults decodes every result of input
+func decodeResults(input string) ([]string, error) {
+	decoder := NewResultDecoder(strings.NewReader(input))
+	var uuids []string
+	for {
+		result, err := decoder.Next()
+		if errors.Is(err, io.EOF) {
+			return uuids, nil
+		}
+		if err != nil {
+			return uuids, err
+		}
+		uuids = append(uuids, result.UUID)
+	}
+}
+
+func TestResultDecoder(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "array",
+			input: testDownload,
+			want:  []string{"r1", "r2"},
+		},
+		{
+			name:  "empty array",
+			input: `[]`,
+			want:  nil,
+		},
+		{
+			name:  "wrapped",
+			input: `{"count":2,"next":null,"results":[{"uuid":"r1"},{"uuid":"r2"}]}`,
+			want:  []string{"r1", "r2"},
+		},
+		{
+			name:  "wrapped null",
+			input: `{"results":null}`,
+			want:  nil,
+		},
+		{
+			name:  "object without results",
+			input: `{"count":0}`,
+			want:  nil,
+		},
+		{
+			name:    "truncated",
+			input:   `[{"uuid":"r1"},{"uuid":`,
+			want:    []string{"r1"},
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			input:   ``,
+			wantErr: true,
+		},
+		{
+			name:    "not an array",
+			input:   `"results"`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeResults(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Next() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Next() results = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newDownloadServer serves content with the given ETag as the download of
+// crawl request test-uuid, honoring Range requests when ranges is true. The
+// returned value holds the header of the last request.
+func newDownloadServer(t *testing.T, content, etag string, ranges bool) (*httptest.Server, *atomic.Value) {
+	var lastHeader atomic.Value
+	lastHeader.Store(http.Header{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/core/crawl-requests/test-uuid/download/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		lastHeader.Store(r.Header.Clone())
+		if !ranges {
+			r.Header.Del("Range")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if etag != "" {
+			w.Header().Set("ETag", etag)
+		}
+		http.ServeContent(w, r, "", time.Time{}, strings.NewReader(content))
+	}))
+	return server, &lastHeader
+}
+
+func TestClient_DownloadCrawlRequestTo(t *testing.T) {
+	server, _ := newDownloadServer(t, testDownload, "", false)
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	var buf bytes.Buffer
+	n, err := client.DownloadCrawlRequestTo(context.Background(), "test-uuid", &buf)
+	if err != nil {
+		t.Fatalf("DownloadCrawlRequestTo() error = %v", err)
+	}
+	if n != int64(len(testDownload)) {
+		t.Errorf("DownloadCrawlRequestTo() = %d, want %d", n, len(testDownload))
+	}
+	if buf.String() != testDownload {
+		t.Errorf("DownloadCrawlRequestTo() wrote %q, want %q", buf.String(), testDownload)
+	}
+
+	if _, err := client.DownloadCrawlRequestTo(context.Background(), "missing", &buf); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DownloadCrawlRequestTo() error = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestClient_StreamCrawlResults(t *testing.T) {
+	server, _ := newDownloadServer(t, testDownload, "", false)
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	stream, err := client.StreamCrawlResults(context.Background(), "test-uuid")
+	if err != nil {
+		t.Fatalf("StreamCrawlResults() error = %v", err)
+	}
+	defer stream.Close()
+
+	var urls []string
+	for {
+		result, err := stream.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		urls = append(urls, result.URL)
+	}
+
+	want := []string{"https://example.com/1", "https://example.com/2"}
+	if !reflect.DeepEqual(urls, want) {
+		t.Errorf("StreamCrawlResults() urls = %v, want %v", urls, want)
+	}
+}
+
+func TestClient_DownloadCrawlRequestToFile(t *testing.T) {
+	tests := []struct {
+		name string
+		part string
+		// validator is the validator saved with the part file
+		validator   string
+		etag        string
+		ranges      bool
+		wantRange   string
+		wantIfRange string
+	}{
+		{
+			name:      "fresh",
+			etag:      `"v1"`,
+			wantRange: "",
+		},
+		{
+			name:        "resume",
+			part:        testDownload[:20],
+			validator:   `"v1"`,
+			etag:        `"v1"`,
+			ranges:      true,
+			wantRange:   "bytes=20-",
+			wantIfRange: `"v1"`,
+		},
+		{
+			name:        "range ignored",
+			part:        testDownload[:20],
+			validator:   `"v1"`,
+			etag:        `"v1"`,
+			wantRange:   "bytes=20-",
+			wantIfRange: `"v1"`,
+		},
+		{
+			name:        "document changed",
+			part:        `[{"uuid":"old","url":"https://example.com/old"}`,
+			validator:   `"v0"`,
+			etag:        `"v1"`,
+			ranges:      true,
+			wantRange:   "bytes=47-",
+			wantIfRange: `"v0"`,
+		},
+		{
+			name:      "no validator",
+			part:      testDownload[:20],
+			etag:      `"v1"`,
+			ranges:    true,
+			wantRange: "",
+		},
+		{
+			name:        "stale part",
+			part:        testDownload + "garbage",
+			validator:   `"v1"`,
+			etag:        `"v1"`,
+			ranges:      true,
+			wantRange:   "",
+			wantIfRange: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, lastHeader := newDownloadServer(t, testDownload, tt.etag, tt.ranges)
+			defer server.Close()
+
+			client := NewClient("test-key", server.URL+"/")
+
+			path := filepath.Join(t.TempDir(), "results.json")
+			if tt.part != "" {
+				if err := os.WriteFile(path+".part", []byte(tt.part), 0o644); err != nil {
+					t.Fatalf("WriteFile() error = %v", err)
+				}
+			}
+			if tt.validator != "" {
+				if err := os.WriteFile(path+".part.validator", []byte(tt.validator), 0o644); err != nil {
+					t.Fatalf("WriteFile() error = %v", err)
+				}
+			}
+
+			n, err := client.DownloadCrawlRequestToFile(context.Background(), "test-uuid", path)
+			if err != nil {
+				t.Fatalf("DownloadCrawlRequestToFile() error = %v", err)
+			}
+			if n != int64(len(testDownload)) {
+				t.Errorf("DownloadCrawlRequestToFile() = %d, want %d", n, len(testDownload))
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("ReadFile() error = %v", err)
+			}
+			if string(got) != testDownload {
+				t.Errorf("file content = %q, want %q", got, testDownload)
+			}
+			for _, leftover := range []string{path + ".part", path + ".part.validator"} {
+				if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+					t.Errorf("%s still exists, Stat() error = %v", filepath.Base(leftover), err)
+				}
+			}
+			header := lastHeader.Load().(http.Header)
+			if got := header.Get("Range"); got != tt.wantRange {
+				t.Errorf("last Range = %q, want %q", got, tt.wantRange)
+			}
+			if got := header.Get("If-Range"); got != tt.wantIfRange {
+				t.Errorf("last If-Range = %q, want %q", got, tt.wantIfRange)
+			}
+		})
+	}
+}
+
+func TestClient_DownloadCrawlRequestToFile_SavesValidator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Length", strconv.Itoa(len(testDownload)))
+		// The connection drops halfway through
+		io.WriteString(w, testDownload[:20])
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	path := filepath.Join(t.TempDir(), "results.json")
+	if _, err := client.DownloadCrawlRequestToFile(context.Background(), "test-uuid", path); err == nil {
+		t.Fatal("DownloadCrawlRequestToFile() error = nil, want error")
+	}
+
+	validator, err := os.ReadFile(path + ".part.validator")
+	if err != nil || string(validator) != `"v1"` {
+		t.Errorf("saved validator = %q, %v, want %q", validator, err, `"v1"`)
+	}
+}
+
+func TestClient_DownloadCrawlRequestToFile_Error(t *testing.T) {
+	server, _ := newDownloadServer(t, testDownload, "", true)
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	path := filepath.Join(t.TempDir(), "results.json")
+	if _, err := client.DownloadCrawlRequestToFile(context.Background(), "missing", path); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DownloadCrawlRequestToFile() error = %v, want %v", err, ErrNotFound)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("target file exists after failed download, Stat() error = %v", err)
+	}
+}
+
+func TestContentRangeStart(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int64
+	}{
+		{"bytes 100-199/200", 100},
+		{"bytes 0-0/*", 0},
+		{"bytes */200", -1},
+		{"items 1-2/3", -1},
+		{"", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := contentRangeStart(tt.value); got != tt.want {
+				t.Errorf("contentRangeStart(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
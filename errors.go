@@ -1,19 +1,177 @@
 package watercrawl
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"sort"
+	"strings"
 )
 
+// Sentinel errors matched by APIError with errors.Is
+var (
+	ErrUnauthorized  = errors.New("watercrawl: unauthorized")
+	ErrNotFound      = errors.New("watercrawl: not found")
+	ErrRateLimited   = errors.New("watercrawl: rate limited")
+	ErrQuotaExceeded = errors.New("watercrawl: quota exceeded")
+)
+
+// requestIDHeaders are the response headers checked for a request ID, in order
+var requestIDHeaders = []string{"X-Request-ID", "X-Correlation-ID", "Request-ID"}
+
 // APIError represents an error returned by the WaterCrawl API
 type APIError struct {
 	StatusCode int
 	Message    string
+
+	// Code is the machine readable error code, if the API sent one
+	Code string
+	// Detail is the "detail" field of the error response, if any
+	Detail string
+	// FieldErrors maps request fields to their validation errors.
+	// Nested fields are joined with dots, e.g. "options.spider_options.max_depth".
+	FieldErrors map[string][]string
+	// RequestID identifies the request in the server logs, if the API sent one
+	RequestID string
+	// Header holds the response headers
+	Header http.Header
+	// Body is the raw response body
+	Body []byte
 }
 
 func (e *APIError) Error() string {
 	return fmt.Sprintf("watercrawl: API error (status %d): %s", e.StatusCode, e.Message)
 }
 
+// Is reports whether the error matches one of the sentinel errors of this package
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrQuotaExceeded:
+		code := strings.ToLower(e.Code)
+		return e.StatusCode == http.StatusPaymentRequired ||
+			strings.Contains(code, "quota") || strings.Contains(code, "credit")
+	}
+	return false
+}
+
+// newAPIError builds an APIError from an error response.
+// It understands {"error": ...}, {"detail": ...}, {"message": ...} and
+// Django REST Framework style per-field errors.
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	}
+
+	for _, name := range requestIDHeaders {
+		if id := resp.Header.Get(name); id != "" {
+			apiErr.RequestID = id
+			break
+		}
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err == nil {
+		var message string
+		for key, raw := range fields {
+			switch key {
+			case "error", "message":
+				if s := rawString(raw); s != "" && (message == "" || key == "error") {
+					message = s
+				}
+			case "detail":
+				apiErr.Detail = rawString(raw)
+			case "code":
+				apiErr.Code = rawString(raw)
+			default:
+				collectFieldErrors(apiErr, key, raw)
+			}
+		}
+
+		apiErr.Message = message
+		if apiErr.Message == "" {
+			apiErr.Message = apiErr.Detail
+		}
+		if apiErr.Message == "" && len(apiErr.FieldErrors) > 0 {
+			apiErr.Message = apiErr.fieldErrorSummary()
+		}
+	}
+
+	// If JSON parsing fails or no error message, use raw body or default message
+	if apiErr.Message == "" {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = fmt.Sprintf("HTTP error %d", resp.StatusCode)
+	}
+
+	return apiErr
+}
+
+// collectFieldErrors records the validation errors of a field, recursing into nested objects
+func collectFieldErrors(apiErr *APIError, field string, raw json.RawMessage) {
+	var messages []string
+	if err := json.Unmarshal(raw, &messages); err == nil {
+		apiErr.addFieldErrors(field, messages...)
+		return
+	}
+
+	if message := rawString(raw); message != "" {
+		apiErr.addFieldErrors(field, message)
+		return
+	}
+
+	var nested map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &nested); err == nil {
+		for key, value := range nested {
+			collectFieldErrors(apiErr, field+"."+key, value)
+		}
+	}
+}
+
+// addFieldErrors appends validation messages for a field
+func (e *APIError) addFieldErrors(field string, messages ...string) {
+	if len(messages) == 0 {
+		return
+	}
+	if e.FieldErrors == nil {
+		e.FieldErrors = make(map[string][]string)
+	}
+	e.FieldErrors[field] = append(e.FieldErrors[field], messages...)
+}
+
+// fieldErrorSummary renders the field errors as a single message, sorted by field
+func (e *APIError) fieldErrorSummary() string {
+	fields := make([]string, 0, len(e.FieldErrors))
+	for field := range e.FieldErrors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		parts = append(parts, fmt.Sprintf("%s: %s", field, strings.Join(e.FieldErrors[field], " ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// rawString returns raw decoded as a JSON string, or "" if it is not a string
+func rawString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return ""
+	}
+	return s
+}
+
 // ValidationError represents a validation error in the SDK
 type ValidationError struct {
 	Field   string
@@ -28,8 +186,49 @@ func (e *ValidationError) Error() string {
 type TimeoutError struct {
 	Operation string
 	Message   string
+
+	// Err is the underlying error, typically context.DeadlineExceeded
+	Err error
 }
 
 func (e *TimeoutError) Error() string {
 	return fmt.Sprintf("watercrawl: timeout error during %s: %s", e.Operation, e.Message)
-} 
\ No newline at end of file
+}
+
+// Unwrap returns the underlying error
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// AbandonedCrawlError is returned when a crawl request owned by a call is
+// abandoned because the caller's context ended. It unwraps to the error
+// that ended the call, so errors.Is(err, context.Canceled) and
+// errors.Is(err, context.DeadlineExceeded) hold as usual.
+type AbandonedCrawlError struct {
+	CrawlRequest string
+
+	// Err is the error the call failed with, wrapping the context error
+	Err error
+
+	// Stopped is true when the crawl request was stopped
+	Stopped bool
+	// StopErr is the error of stopping the crawl request, nil if it was
+	// stopped or left running as configured by WithStopAbandonedCrawls
+	StopErr error
+}
+
+func (e *AbandonedCrawlError) Error() string {
+	switch {
+	case e.StopErr != nil:
+		return fmt.Sprintf("watercrawl: crawl request %s abandoned: %v (stopping it failed: %v)", e.CrawlRequest, e.Err, e.StopErr)
+	case e.Stopped:
+		return fmt.Sprintf("watercrawl: crawl request %s abandoned and stopped: %v", e.CrawlRequest, e.Err)
+	default:
+		return fmt.Sprintf("watercrawl: crawl request %s abandoned and left running: %v", e.CrawlRequest, e.Err)
+	}
+}
+
+// Unwrap returns the error the call failed with
+func (e *AbandonedCrawlError) Unwrap() error {
+	return e.Err
+}
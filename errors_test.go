@@ -0,0 +1,138 @@
+package watercrawl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name            string
+		statusCode      int
+		header          http.Header
+		body            string
+		wantMessage     string
+		wantDetail      string
+		wantCode        string
+		wantFieldErrors map[string][]string
+		wantRequestID   string
+	}{
+		{
+			name:        "error field",
+			statusCode:  http.StatusBadRequest,
+			body:        `{"error":"Invalid request parameters"}`,
+			wantMessage: "Invalid request parameters",
+		},
+		{
+			name:          "detail field with code and request ID",
+			statusCode:    http.StatusUnauthorized,
+			header:        http.Header{"X-Request-Id": []string{"req-123"}},
+			body:          `{"detail":"Invalid API key.","code":"authentication_failed"}`,
+			wantMessage:   "Invalid API key.",
+			wantDetail:    "Invalid API key.",
+			wantCode:      "authentication_failed",
+			wantRequestID: "req-123",
+		},
+		{
+			name:        "field errors",
+			statusCode:  http.StatusBadRequest,
+			body:        `{"url":["Enter a valid URL."],"options":{"spider_options":{"max_depth":["Must be positive."]}},"non_field_errors":"Bad combination."}`,
+			wantMessage: "non_field_errors: Bad combination.; options.spider_options.max_depth: Must be positive.; url: Enter a valid URL.",
+			wantFieldErrors: map[string][]string{
+				"url":                              {"Enter a valid URL."},
+				"options.spider_options.max_depth": {"Must be positive."},
+				"non_field_errors":                 {"Bad combination."},
+			},
+		},
+		{
+			name:        "plain text body",
+			statusCode:  http.StatusBadGateway,
+			body:        "Bad Gateway\n",
+			wantMessage: "Bad Gateway",
+		},
+		{
+			name:        "empty body",
+			statusCode:  http.StatusInternalServerError,
+			body:        "",
+			wantMessage: "HTTP error 500",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := tt.header
+			if header == nil {
+				header = http.Header{}
+			}
+			resp := &http.Response{StatusCode: tt.statusCode, Header: header}
+
+			apiErr := newAPIError(resp, []byte(tt.body))
+
+			if apiErr.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", apiErr.Message, tt.wantMessage)
+			}
+			if apiErr.Detail != tt.wantDetail {
+				t.Errorf("Detail = %q, want %q", apiErr.Detail, tt.wantDetail)
+			}
+			if apiErr.Code != tt.wantCode {
+				t.Errorf("Code = %q, want %q", apiErr.Code, tt.wantCode)
+			}
+			if !reflect.DeepEqual(apiErr.FieldErrors, tt.wantFieldErrors) {
+				t.Errorf("FieldErrors = %v, want %v", apiErr.FieldErrors, tt.wantFieldErrors)
+			}
+			if apiErr.RequestID != tt.wantRequestID {
+				t.Errorf("RequestID = %q, want %q", apiErr.RequestID, tt.wantRequestID)
+			}
+			if string(apiErr.Body) != tt.body {
+				t.Errorf("Body = %q, want %q", apiErr.Body, tt.body)
+			}
+		})
+	}
+}
+
+func TestAPIError_Is(t *testing.T) {
+	tests := []struct {
+		err  *APIError
+		want error
+	}{
+		{err: &APIError{StatusCode: http.StatusUnauthorized}, want: ErrUnauthorized},
+		{err: &APIError{StatusCode: http.StatusNotFound}, want: ErrNotFound},
+		{err: &APIError{StatusCode: http.StatusTooManyRequests}, want: ErrRateLimited},
+		{err: &APIError{StatusCode: http.StatusPaymentRequired}, want: ErrQuotaExceeded},
+		{err: &APIError{StatusCode: http.StatusForbidden, Code: "quota_exceeded"}, want: ErrQuotaExceeded},
+	}
+
+	sentinels := []error{ErrUnauthorized, ErrNotFound, ErrRateLimited, ErrQuotaExceeded}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d %s", tt.err.StatusCode, tt.err.Code), func(t *testing.T) {
+			wrapped := fmt.Errorf("operation failed: %w", tt.err)
+			for _, sentinel := range sentinels {
+				if got := errors.Is(wrapped, sentinel); got != (sentinel == tt.want) {
+					t.Errorf("errors.Is(%v, %v) = %v, want %v", tt.err, sentinel, got, sentinel == tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_DownloadCrawlRequest_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"detail":"Not found."}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	_, err := client.DownloadCrawlRequest(context.Background(), "missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("DownloadCrawlRequest() error = %v, want %v", err, ErrNotFound)
+	}
+}
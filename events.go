@@ -0,0 +1,156 @@
+package watercrawl
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Event is a typed event decoded from the crawl status stream.
+// It is implemented by StateEvent, ResultEvent, ProgressEvent, ErrorEvent and UnknownEvent.
+type Event interface {
+	// EventType returns the type of the event
+	EventType() EventType
+	isEvent()
+}
+
+// StateEvent carries the current state of the crawl request
+type StateEvent struct {
+	Request CrawlRequest
+}
+
+// ResultEvent carries a single crawled page
+type ResultEvent struct {
+	Result CrawlResult
+}
+
+// ProgressEvent reports the progress of the crawl
+type ProgressEvent struct {
+	Progress float64     `json:"progress"`
+	Status   CrawlStatus `json:"status,omitempty"`
+}
+
+// ErrorEvent reports an error that occurred while crawling
+type ErrorEvent struct {
+	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
+}
+
+// UnknownEvent is any event this package does not decode into a dedicated type
+type UnknownEvent struct {
+	Type EventType
+	Data json.RawMessage
+}
+
+// EventType returns EventTypeState
+func (StateEvent) EventType() EventType { return EventTypeState }
+
+// EventType returns EventTypeResult
+func (ResultEvent) EventType() EventType { return EventTypeResult }
+
+// EventType returns EventTypeProgress
+func (ProgressEvent) EventType() EventType { return EventTypeProgress }
+
+// EventType returns EventTypeError
+func (ErrorEvent) EventType() EventType { return EventTypeError }
+
+// EventType returns the type of the event as received
+func (e UnknownEvent) EventType() EventType { return e.Type }
+
+func (StateEvent) isEvent()    {}
+func (ResultEvent) isEvent()   {}
+func (ProgressEvent) isEvent() {}
+func (ErrorEvent) isEvent()    {}
+func (UnknownEvent) isEvent()  {}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the raw event data in RawData
+func (m *EventStreamMessage) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type EventType       `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var value interface{}
+	if len(raw.Data) > 0 {
+		if err := json.Unmarshal(raw.Data, &value); err != nil {
+			return err
+		}
+	}
+
+	m.Type = raw.Type
+	m.Data = value
+	m.RawData = raw.Data
+	return nil
+}
+
+// Event decodes the message into its typed form.
+// Decoding uses RawData, so it reflects the data as received from the server
+// even when Data has been replaced by downloaded results.
+func (m *EventStreamMessage) Event() (Event, error) {
+	data := m.RawData
+	if len(data) == 0 {
+		// The message was built in code rather than decoded
+		var err error
+		data, err = json.Marshal(m.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s event data: %w", m.Type, err)
+		}
+	}
+
+	switch m.Type {
+	case EventTypeState:
+		var event StateEvent
+		if err := json.Unmarshal(data, &event.Request); err != nil {
+			return nil, fmt.Errorf("failed to decode state event: %w", err)
+		}
+		return event, nil
+	case EventTypeResult:
+		var event ResultEvent
+		if err := json.Unmarshal(data, &event.Result); err != nil {
+			return nil, fmt.Errorf("failed to decode result event: %w", err)
+		}
+		return event, nil
+	case EventTypeProgress:
+		var event ProgressEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, fmt.Errorf("failed to decode progress event: %w", err)
+		}
+		return event, nil
+	case EventTypeError:
+		return decodeErrorEvent(data)
+	default:
+		return UnknownEvent{Type: m.Type, Data: append(json.RawMessage(nil), data...)}, nil
+	}
+}
+
+// decodeErrorEvent decodes error event data sent either as a string or as an object
+func decodeErrorEvent(data []byte) (ErrorEvent, error) {
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		return ErrorEvent{Message: message}, nil
+	}
+
+	var fields struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+		Detail  string `json:"detail"`
+		Code    string `json:"code"`
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return ErrorEvent{}, fmt.Errorf("failed to decode error event: %w", err)
+	}
+
+	event := ErrorEvent{Message: fields.Message, Code: fields.Code}
+	if event.Message == "" {
+		event.Message = fields.Error
+	}
+	if event.Message == "" {
+		event.Message = fields.Detail
+	}
+	if event.Message == "" {
+		event.Message = string(data)
+	}
+	return event, nil
+}
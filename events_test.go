@@ -0,0 +1,118 @@
+package watercrawl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEventStreamMessage_Event(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		check func(t *testing.T, event Event)
+	}{
+		{
+			name:  "state event",
+			input: `{"type":"state","data":{"uuid":"test-uuid","status":"running","progress":25}}`,
+			check: func(t *testing.T, event Event) {
+				state, ok := event.(StateEvent)
+				if !ok {
+					t.Fatalf("Expected StateEvent, got %T", event)
+				}
+				if state.Request.UUID != "test-uuid" || state.Request.Status != CrawlStatusRunning {
+					t.Errorf("StateEvent.Request = %+v, want uuid test-uuid and status running", state.Request)
+				}
+			},
+		},
+		{
+			name:  "result event",
+			input: `{"type":"result","data":{"uuid":"result-uuid","url":"https://example.com","data":{"markdown":"# Example"}}}`,
+			check: func(t *testing.T, event Event) {
+				result, ok := event.(ResultEvent)
+				if !ok {
+					t.Fatalf("Expected ResultEvent, got %T", event)
+				}
+				if result.Result.URL != "https://example.com" {
+					t.Errorf("ResultEvent.Result.URL = %v, want %v", result.Result.URL, "https://example.com")
+				}
+			},
+		},
+		{
+			name:  "progress event",
+			input: `{"type":"progress","data":{"progress":50.5}}`,
+			check: func(t *testing.T, event Event) {
+				progress, ok := event.(ProgressEvent)
+				if !ok {
+					t.Fatalf("Expected ProgressEvent, got %T", event)
+				}
+				if progress.Progress != 50.5 {
+					t.Errorf("ProgressEvent.Progress = %v, want %v", progress.Progress, 50.5)
+				}
+			},
+		},
+		{
+			name:  "error event as string",
+			input: `{"type":"error","data":"browser crashed"}`,
+			check: func(t *testing.T, event Event) {
+				if e, ok := event.(ErrorEvent); !ok || e.Message != "browser crashed" {
+					t.Errorf("Expected ErrorEvent with message, got %#v", event)
+				}
+			},
+		},
+		{
+			name:  "error event as object",
+			input: `{"type":"error","data":{"error":"quota exceeded","code":"quota"}}`,
+			check: func(t *testing.T, event Event) {
+				if e, ok := event.(ErrorEvent); !ok || e.Message != "quota exceeded" || e.Code != "quota" {
+					t.Errorf("Expected ErrorEvent with message and code, got %#v", event)
+				}
+			},
+		},
+		{
+			name:  "unknown event",
+			input: `{"type":"heartbeat","data":{"at":1}}`,
+			check: func(t *testing.T, event Event) {
+				unknown, ok := event.(UnknownEvent)
+				if !ok {
+					t.Fatalf("Expected UnknownEvent, got %T", event)
+				}
+				if unknown.EventType() != "heartbeat" || string(unknown.Data) != `{"at":1}` {
+					t.Errorf("UnknownEvent = %+v, want heartbeat with raw data", unknown)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var message EventStreamMessage
+			if err := json.Unmarshal([]byte(tt.input), &message); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if _, ok := message.Data.(map[string]interface{}); !ok && message.Type != EventTypeError {
+				t.Errorf("Data = %T, want map[string]interface{}", message.Data)
+			}
+
+			event, err := message.Event()
+			if err != nil {
+				t.Fatalf("Event() error = %v", err)
+			}
+			tt.check(t, event)
+		})
+	}
+}
+
+func TestEventStreamMessage_Event_WithoutRawData(t *testing.T) {
+	message := &EventStreamMessage{
+		Type: EventTypeProgress,
+		Data: map[string]interface{}{"progress": 10.0},
+	}
+
+	event, err := message.Event()
+	if err != nil {
+		t.Fatalf("Event() error = %v", err)
+	}
+	if progress, ok := event.(ProgressEvent); !ok || progress.Progress != 10 {
+		t.Errorf("Event() = %#v, want ProgressEvent with progress 10", event)
+	}
+}
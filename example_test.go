@@ -24,19 +24,17 @@ func Example() {
 
 	// Example 2: Create a crawl request with options
 	input := watercrawl.CreateCrawlRequestInput{
-		URL: "https://example.com",
+		URL: watercrawl.URLs{"https://example.com"},
 		Options: watercrawl.CrawlOptions{
-			SpiderOptions: map[string]interface{}{
-				"allowed_domains": []string{"example.com"},
-				"max_depth":      2,
+			SpiderOptions: watercrawl.SpiderOptions{
+				AllowedDomains: []string{"example.com"},
+				MaxDepth:       watercrawl.Int(2),
 			},
-			PageOptions: map[string]interface{}{
-				"wait_for": "#content",
-				"timeout":  30,
-			},
-			PluginOptions: map[string]interface{}{
-				"extract_links": true,
-				"extract_text": true,
+			PageOptions: watercrawl.PageOptions{
+				WaitTime:        watercrawl.Int(1000),
+				Timeout:         30000,
+				OnlyMainContent: watercrawl.Bool(true),
+				IncludeLinks:    watercrawl.Bool(true),
 			},
 		},
 	}
@@ -55,9 +53,9 @@ func Example() {
 
 	for event := range events {
 		switch event.Type {
-		case "progress":
+		case watercrawl.EventTypeProgress:
 			fmt.Printf("Progress update: %v\n", event.Data)
-		case "result":
+		case watercrawl.EventTypeResult:
 			fmt.Printf("Got result: %v\n", event.Data)
 		}
 	}
@@ -84,19 +82,15 @@ func ExampleClient_ScrapeURL() {
 	client := watercrawl.NewClient("your-api-key", "")
 	ctx := context.Background()
 
-	// Define page and plugin options
-	pageOptions := map[string]interface{}{
-		"wait_for": "#main-content",
-		"timeout":  30,
-	}
-
-	pluginOptions := map[string]interface{}{
-		"extract_links": true,
-		"extract_text":  true,
+	// Define page options
+	pageOptions := &watercrawl.PageOptions{
+		IncludeTags:     []string{"main"},
+		Timeout:         30000,
+		OnlyMainContent: watercrawl.Bool(true),
 	}
 
 	// Scrape URL synchronously with automatic result download
-	result, err := client.ScrapeURL(ctx, "https://example.com", pageOptions, pluginOptions, true, true)
+	result, err := client.ScrapeURL(ctx, "https://example.com", pageOptions, nil, true, true)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -110,10 +104,10 @@ func ExampleClient_MonitorCrawlRequest() {
 
 	// Create a crawl request first
 	input := watercrawl.CreateCrawlRequestInput{
-		URL: "https://example.com",
+		URL: watercrawl.URLs{"https://example.com"},
 		Options: watercrawl.CrawlOptions{
-			SpiderOptions: map[string]interface{}{
-				"allowed_domains": []string{"example.com"},
+			SpiderOptions: watercrawl.SpiderOptions{
+				AllowedDomains: []string{"example.com"},
 			},
 		},
 	}
@@ -131,12 +125,12 @@ func ExampleClient_MonitorCrawlRequest() {
 
 	for event := range events {
 		switch event.Type {
-		case "progress":
+		case watercrawl.EventTypeProgress:
 			fmt.Printf("Progress: %v\n", event.Data)
-		case "result":
+		case watercrawl.EventTypeResult:
 			fmt.Printf("Result: %v\n", event.Data)
-		case "error":
+		case watercrawl.EventTypeError:
 			fmt.Printf("Error: %v\n", event.Data)
 		}
 	}
-} 
\ No newline at end of file
+}
@@ -0,0 +1,218 @@
+package watercrawl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// IteratorOptions configures a pagination iterator
+type IteratorOptions struct {
+	// PageSize is the number of items requested per page; 0 uses the server default
+	PageSize int
+
+	// Prefetch fetches the next page in the background while the current one
+	// is consumed. Background fetches are not bound to the ctx passed to
+	// Next; call Iterator.Close to cancel them.
+	Prefetch bool
+}
+
+// Iterator iterates over the items of a paginated API list, following the
+// server's next links. It is not safe for concurrent use.
+//
+//	it := client.IterateCrawlRequests(watercrawl.IteratorOptions{PageSize: 50})
+//	defer it.Close()
+//	for it.Next(ctx) {
+//		request := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	fetch    func(ctx context.Context, query url.Values) ([]T, *string, error)
+	query    url.Values
+	prefetch bool
+	// prefetchCtx bounds background fetches, cancelled by Close
+	prefetchCtx context.Context
+	cancel      context.CancelFunc
+	pending     chan iteratorPage[T]
+	items       []T
+	index       int
+	current     T
+	done        bool
+	err         error
+}
+
+// iteratorPage is the outcome of fetching one page
+type iteratorPage[T any] struct {
+	items []T
+	next  *string
+	err   error
+}
+
+// newIterator creates an iterator starting at the page described by query
+func newIterator[T any](query url.Values, opts IteratorOptions, fetch func(ctx context.Context, query url.Values) ([]T, *string, error)) *Iterator[T] {
+	if opts.PageSize > 0 {
+		query.Set("page_size", strconv.Itoa(opts.PageSize))
+	}
+	it := &Iterator[T]{
+		fetch:    fetch,
+		query:    query,
+		prefetch: opts.Prefetch,
+	}
+	if it.prefetch {
+		it.prefetchCtx, it.cancel = context.WithCancel(context.Background())
+	}
+	return it
+}
+
+// Next advances to the next item, fetching pages as needed.
+// It returns false at the end of the list, on error or when ctx is done.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	for {
+		if it.index < len(it.items) {
+			it.current = it.items[it.index]
+			it.index++
+			return true
+		}
+		if it.err != nil || it.done {
+			return false
+		}
+		if err := ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+
+		page := it.nextPage(ctx)
+		if page.err != nil {
+			it.err = page.err
+			it.items = nil
+			return false
+		}
+
+		it.items = page.items
+		it.index = 0
+		it.advance(page.next)
+	}
+}
+
+// Value returns the current item
+func (it *Iterator[T]) Value() T {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Close stops the iteration and cancels a background fetch in progress.
+// Next returns false afterwards.
+func (it *Iterator[T]) Close() error {
+	if it.cancel != nil {
+		it.cancel()
+	}
+	it.pending = nil
+	it.items = nil
+	it.done = true
+	return nil
+}
+
+// nextPage returns the prefetched page or fetches the current one
+func (it *Iterator[T]) nextPage(ctx context.Context) iteratorPage[T] {
+	if it.pending != nil {
+		pending := it.pending
+		it.pending = nil
+		select {
+		case page := <-pending:
+			return page
+		case <-ctx.Done():
+			return iteratorPage[T]{err: ctx.Err()}
+		}
+	}
+
+	items, next, err := it.fetch(ctx, it.query)
+	return iteratorPage[T]{items: items, next: next, err: err}
+}
+
+// advance moves the iterator to the page referenced by next, prefetching it if enabled
+func (it *Iterator[T]) advance(next *string) {
+	if next == nil || *next == "" {
+		it.done = true
+		return
+	}
+
+	nextURL, err := url.Parse(*next)
+	if err != nil {
+		it.err = fmt.Errorf("invalid next page link %q: %w", *next, err)
+		return
+	}
+	// Only the query is taken from the link so requests keep going through the
+	// configured base URL, even when the server reports an internal host name
+	it.query = nextURL.Query()
+
+	if it.prefetch {
+		pending := make(chan iteratorPage[T], 1)
+		query := it.query
+		go func() {
+			items, next, err := it.fetch(it.prefetchCtx, query)
+			pending <- iteratorPage[T]{items: items, next: next, err: err}
+		}()
+		it.pending = pending
+	}
+}
+
+// IterateCrawlRequests returns an iterator over all crawl requests
+func (c *Client) IterateCrawlRequests(opts IteratorOptions) *Iterator[CrawlRequest] {
+	return newIterator(url.Values{}, opts, func(ctx context.Context, query url.Values) ([]CrawlRequest, *string, error) {
+		list, err := c.getCrawlRequestsPage(ctx, query)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Results, list.Next, nil
+	})
+}
+
+// IterateCrawlRequestResults returns an iterator over all results of a crawl request
+func (c *Client) IterateCrawlRequestResults(id string, opts IteratorOptions) *Iterator[CrawlResult] {
+	return newIterator(url.Values{}, opts, func(ctx context.Context, query url.Values) ([]CrawlResult, *string, error) {
+		list, err := c.getCrawlRequestResultsPage(ctx, id, query)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Results, list.Next, nil
+	})
+}
+
+// getCrawlRequestsPage retrieves one page of crawl requests
+func (c *Client) getCrawlRequestsPage(ctx context.Context, query url.Values) (*CrawlRequestList, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, c.apiPath("core/crawl-requests/"), query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result CrawlRequestList
+	if err := c.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// getCrawlRequestResultsPage retrieves one page of crawl request results
+func (c *Client) getCrawlRequestResultsPage(ctx context.Context, id string, query url.Values) (*CrawlResultList, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, c.apiPath("core/crawl-requests/%s/results/", id), query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result CrawlResultList
+	if err := c.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,31 @@
+//go:build go1.23
+
+package watercrawl
+
+import (
+	"context"
+	"iter"
+)
+
+// All returns an iter.Seq2 over the remaining items. Iteration stops at the
+// end of the list or after yielding a non-nil error as the final pair.
+//
+//	for request, err := range client.IterateCrawlRequests(opts).All(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (it *Iterator[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for it.Next(ctx) {
+			if !yield(it.Value(), nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
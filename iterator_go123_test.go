@@ -0,0 +1,30 @@
+//go:build go1.23
+
+package watercrawl
+
+import (
+	"context"
+	"testing"
+)
+
+func TestIterator_All(t *testing.T) {
+	server := newPaginatedServer(t, 5)
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	count := 0
+	for request, err := range client.IterateCrawlRequests(IteratorOptions{PageSize: 2}).All(context.Background()) {
+		if err != nil {
+			t.Fatalf("All() error = %v", err)
+		}
+		if request.UUID == "" {
+			t.Error("All() yielded an empty crawl request")
+		}
+		count++
+	}
+
+	if count != 5 {
+		t.Errorf("All() yielded %d items, want 5", count)
+	}
+}
@@ -0,0 +1,194 @@
+package watercrawl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// newPaginatedServer serves total crawl requests in pages, with next links
+// pointing at an unreachable host to check that only their query is used
+func newPaginatedServer(t *testing.T, total int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/core/crawl-requests/" {
+			t.Errorf("Expected path /api/v1/core/crawl-requests/, got %s", r.URL.Path)
+		}
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+		if pageSize == 0 {
+			pageSize = 10
+		}
+
+		list := CrawlRequestList{Count: total}
+		for i := (page - 1) * pageSize; i < page*pageSize && i < total; i++ {
+			list.Results = append(list.Results, CrawlRequest{UUID: fmt.Sprintf("uuid-%d", i)})
+		}
+		if page*pageSize < total {
+			next := fmt.Sprintf("http://internal:8000/api/v1/core/crawl-requests/?page=%d&page_size=%d", page+1, pageSize)
+			list.Next = &next
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(list); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+}
+
+func TestClient_IterateCrawlRequests(t *testing.T) {
+	for _, prefetch := range []bool{false, true} {
+		t.Run(fmt.Sprintf("prefetch=%v", prefetch), func(t *testing.T) {
+			server := newPaginatedServer(t, 7)
+			defer server.Close()
+
+			client := NewClient("test-key", server.URL+"/")
+			it := client.IterateCrawlRequests(IteratorOptions{PageSize: 3, Prefetch: prefetch})
+
+			var uuids []string
+			for it.Next(context.Background()) {
+				uuids = append(uuids, it.Value().UUID)
+			}
+			if err := it.Err(); err != nil {
+				t.Fatalf("Err() = %v", err)
+			}
+
+			if len(uuids) != 7 {
+				t.Fatalf("iterated %d items, want 7: %v", len(uuids), uuids)
+			}
+			for i, uuid := range uuids {
+				if want := fmt.Sprintf("uuid-%d", i); uuid != want {
+					t.Errorf("item %d = %s, want %s", i, uuid, want)
+				}
+			}
+			if it.Next(context.Background()) {
+				t.Error("Next() after end = true, want false")
+			}
+		})
+	}
+}
+
+func TestClient_IterateCrawlRequests_ContextCancelled(t *testing.T) {
+	server := newPaginatedServer(t, 7)
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+	it := client.IterateCrawlRequests(IteratorOptions{PageSize: 3})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	count := 0
+	for it.Next(ctx) {
+		count++
+		if count == 3 {
+			cancel()
+		}
+	}
+
+	if count != 3 {
+		t.Errorf("iterated %d items, want 3", count)
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("Err() = %v, want %v", it.Err(), context.Canceled)
+	}
+}
+
+func TestClient_IterateCrawlRequests_PrefetchPerCallContext(t *testing.T) {
+	server := newPaginatedServer(t, 7)
+	defer server.Close()
+
+	// Slow pages keep each prefetch in flight after the Next call that started it
+	httpClient := &http.Client{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			time.Sleep(20 * time.Millisecond)
+			return http.DefaultTransport.RoundTrip(r)
+		}),
+	}
+	client := NewClient("test-key", server.URL+"/", WithHTTPClient(httpClient))
+	it := client.IterateCrawlRequests(IteratorOptions{PageSize: 3, Prefetch: true})
+	defer it.Close()
+
+	count := 0
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		ok := it.Next(ctx)
+		cancel()
+		if !ok {
+			break
+		}
+		count++
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	if count != 7 {
+		t.Errorf("iterated %d items, want 7", count)
+	}
+}
+
+func TestIterator_Close(t *testing.T) {
+	server := newPaginatedServer(t, 7)
+	defer server.Close()
+
+	canceled := make(chan struct{})
+	httpClient := &http.Client{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if r.URL.Query().Get("page") == "2" {
+				<-r.Context().Done()
+				close(canceled)
+				return nil, r.Context().Err()
+			}
+			return http.DefaultTransport.RoundTrip(r)
+		}),
+	}
+	client := NewClient("test-key", server.URL+"/", WithHTTPClient(httpClient))
+	it := client.IterateCrawlRequests(IteratorOptions{PageSize: 3, Prefetch: true})
+
+	if !it.Next(context.Background()) {
+		t.Fatalf("Next() = false, Err() = %v", it.Err())
+	}
+	if err := it.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+
+	select {
+	case <-canceled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("prefetch not cancelled by Close()")
+	}
+	if it.Next(context.Background()) {
+		t.Error("Next() after Close() = true, want false")
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Err() after Close() = %v, want nil", err)
+	}
+}
+
+func TestClient_IterateCrawlRequestResults_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+	it := client.IterateCrawlRequestResults("missing", IteratorOptions{})
+
+	if it.Next(context.Background()) {
+		t.Error("Next() = true, want false")
+	}
+	var apiErr *APIError
+	if !errors.As(it.Err(), &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Err() = %v, want APIError with status 404", it.Err())
+	}
+}
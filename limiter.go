@@ -0,0 +1,124 @@
+package watercrawl
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits outgoing requests to requestsPerSecond with bursts of up to burst requests.
+// The limit is shared by every goroutine using the client, and retries count against it.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(c *Client) {
+		if requestsPerSecond <= 0 {
+			c.rateLimiter = nil
+			return
+		}
+		c.rateLimiter = newTokenBucket(requestsPerSecond, burst)
+	}
+}
+
+// WithMaxConcurrentRequests caps the number of requests in flight at once.
+// A request stays in flight until its response body is closed, except for
+// event streams from MonitorCrawlRequest, which only hold a slot until their
+// headers are received so that requests made while monitoring can proceed.
+func WithMaxConcurrentRequests(n int) Option {
+	return func(c *Client) {
+		if n <= 0 {
+			c.concurrency = nil
+			return
+		}
+		c.concurrency = make(chan struct{}, n)
+	}
+}
+
+// tokenBucket is a token bucket rate limiter
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket creates a full token bucket
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until a token is available or ctx is done
+func (b *tokenBucket) wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	// Reserve a token, possibly going into debt, and wait for the debt to be repaid
+	b.tokens--
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if err := sleepContext(ctx, delay); err != nil {
+		// Give the reserved token back
+		b.mu.Lock()
+		b.tokens++
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// acquire waits for the rate limiter and a concurrency slot.
+// The returned release function frees the slot and is safe to call more than once.
+func (c *Client) acquire(ctx context.Context) (func(), error) {
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.concurrency == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case c.concurrency <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-c.concurrency })
+	}, nil
+}
+
+// releaseOnClose releases a concurrency slot when the response body is closed
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	defer r.release()
+	return r.ReadCloser.Close()
+}
@@ -0,0 +1,146 @@
+package watercrawl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket_wait(t *testing.T) {
+	bucket := newTokenBucket(100, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := bucket.wait(ctx); err != nil {
+			t.Fatalf("wait() error = %v", err)
+		}
+	}
+
+	// Two tokens come from the burst, the other two take 10ms each
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("4 waits with burst 2 at 100 req/s took %v, want at least 15ms", elapsed)
+	}
+}
+
+func TestTokenBucket_waitCancelled(t *testing.T) {
+	bucket := newTokenBucket(0.001, 1)
+	if err := bucket.wait(context.Background()); err != nil {
+		t.Fatalf("wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := bucket.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wait() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestClient_MaxConcurrentRequests(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/", WithMaxConcurrentRequests(2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.StopCrawlRequest(context.Background(), "test-uuid"); err != nil {
+				t.Errorf("StopCrawlRequest() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&maxInFlight); got > 2 {
+		t.Errorf("max in-flight requests = %d, want at most 2", got)
+	}
+}
+
+func TestClient_MaxConcurrentRequests_ContextCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/", WithMaxConcurrentRequests(1))
+
+	// Hold the only slot by keeping a response body open
+	resp, err := client.doRequest(context.Background(), http.MethodGet, "/test", nil, nil)
+	if err != nil {
+		t.Fatalf("doRequest() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.doRequest(ctx, http.MethodGet, "/test", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("doRequest() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// Closing the body frees the slot
+	resp.Body.Close()
+	resp, err = client.doRequest(context.Background(), http.MethodGet, "/test", nil, nil)
+	if err != nil {
+		t.Fatalf("doRequest() after release error = %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestClient_MaxConcurrentRequests_EventStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/core/crawl-requests/":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"uuid":"test-uuid","status":"new"}`)
+		case "/api/v1/core/crawl-requests/test-uuid/status/":
+			// The stream stays open after the result event
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"type\":\"result\",\"data\":{\"uuid\":\"result-uuid\",\"url\":\"https://example.com\"}}\n\n")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		case "/api/v1/core/crawl-requests/test-uuid/download/":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `[{"uuid":"downloaded-uuid","url":"https://example.com"}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/", WithMaxConcurrentRequests(1))
+
+	// The download happens while the status stream is still open
+	start := time.Now()
+	result, err := client.Scrape(context.Background(), "https://example.com", &ScrapeOptions{Download: true})
+	if err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	if result.Source != ScrapeSourceDownload {
+		t.Errorf("Scrape() source = %s, want %s", result.Source, ScrapeSourceDownload)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Scrape() took %s, want the download not to wait for the stream", elapsed)
+	}
+}
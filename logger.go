@@ -0,0 +1,91 @@
+package watercrawl
+
+import (
+	"fmt"
+	"io"
+	"log"
+)
+
+// LogLevel is the severity of a log message
+type LogLevel int
+
+const (
+	// LogLevelDebug enables request/response tracing and SSE frame dumps
+	LogLevelDebug LogLevel = iota
+	// LogLevelInfo enables informational messages about crawl progress
+	LogLevelInfo
+	// LogLevelWarn enables recoverable problems such as failed downloads
+	LogLevelWarn
+	// LogLevelError enables errors only
+	LogLevelError
+)
+
+// String returns the name of the log level
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int(l))
+	}
+}
+
+// Logger is the interface used by the client for diagnostic output.
+// The client never passes the API key to the logger.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// NewLogger returns a Logger writing messages at or above level to w
+func NewLogger(w io.Writer, level LogLevel) Logger {
+	return &stdLogger{
+		logger: log.New(w, "watercrawl: ", log.LstdFlags),
+		level:  level,
+	}
+}
+
+// stdLogger is a leveled Logger backed by the standard library log package
+type stdLogger struct {
+	logger *log.Logger
+	level  LogLevel
+}
+
+func (l *stdLogger) Debugf(format string, args ...interface{}) {
+	l.logf(LogLevelDebug, format, args...)
+}
+
+func (l *stdLogger) Infof(format string, args ...interface{}) {
+	l.logf(LogLevelInfo, format, args...)
+}
+
+func (l *stdLogger) Warnf(format string, args ...interface{}) {
+	l.logf(LogLevelWarn, format, args...)
+}
+
+func (l *stdLogger) Errorf(format string, args ...interface{}) {
+	l.logf(LogLevelError, format, args...)
+}
+
+func (l *stdLogger) logf(level LogLevel, format string, args ...interface{}) {
+	if level < l.level {
+		return
+	}
+	l.logger.Printf("[%s] %s", level, fmt.Sprintf(format, args...))
+}
+
+// nopLogger discards all messages; it is the default Logger of a Client
+type nopLogger struct{}
+
+func (nopLogger) Debugf(format string, args ...interface{}) {}
+func (nopLogger) Infof(format string, args ...interface{})  {}
+func (nopLogger) Warnf(format string, args ...interface{})  {}
+func (nopLogger) Errorf(format string, args ...interface{}) {}
@@ -0,0 +1,64 @@
+package watercrawl
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewLogger_Levels(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, LogLevelWarn)
+
+	logger.Debugf("debug %d", 1)
+	logger.Infof("info %d", 2)
+	logger.Warnf("warn %d", 3)
+	logger.Errorf("error %d", 4)
+
+	output := buf.String()
+	if strings.Contains(output, "debug 1") || strings.Contains(output, "info 2") {
+		t.Errorf("Expected debug and info messages to be filtered, got %q", output)
+	}
+	if !strings.Contains(output, "[WARN] warn 3") {
+		t.Errorf("Expected warn message in output, got %q", output)
+	}
+	if !strings.Contains(output, "[ERROR] error 4") {
+		t.Errorf("Expected error message in output, got %q", output)
+	}
+}
+
+func TestClient_Logger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := NewClient("secret-api-key", server.URL+"/", WithLogger(NewLogger(&buf, LogLevelDebug)))
+
+	resp, err := client.doRequest(context.Background(), http.MethodGet, "/test", nil, nil)
+	if err != nil {
+		t.Fatalf("doRequest() error = %v", err)
+	}
+	if err := client.processResponse(resp, nil); err != nil {
+		t.Fatalf("processResponse() error = %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "Making request to: GET") {
+		t.Errorf("Expected request trace in output, got %q", output)
+	}
+	if strings.Contains(output, "secret-api-key") {
+		t.Errorf("API key leaked into log output: %q", output)
+	}
+}
+
+func TestClient_DefaultLoggerIsSilent(t *testing.T) {
+	client := NewClient("test-key", "")
+	if _, ok := client.logger.(nopLogger); !ok {
+		t.Errorf("Expected default logger to be nopLogger, got %T", client.logger)
+	}
+}
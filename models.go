@@ -1,21 +1,23 @@
 package watercrawl
 
+import "encoding/json"
+
 // CrawlRequest represents a crawl request
 type CrawlRequest struct {
-	UUID      string         `json:"uuid"`
-	URL       interface{}    `json:"url"` // Can be string or []string
-	Status    string         `json:"status"`
-	Progress  float64        `json:"progress"`
-	Options   CrawlOptions   `json:"options"`
-	CreatedAt string         `json:"created_at"`
-	UpdatedAt string         `json:"updated_at"`
+	UUID      string       `json:"uuid"`
+	URL       URLs         `json:"url"`
+	Status    CrawlStatus  `json:"status"`
+	Progress  float64      `json:"progress"`
+	Options   CrawlOptions `json:"options"`
+	CreatedAt Timestamp    `json:"created_at"`
+	UpdatedAt Timestamp    `json:"updated_at"`
 }
 
 // CrawlOptions represents the options for a crawl request
 type CrawlOptions struct {
-	SpiderOptions  map[string]interface{} `json:"spider_options"`
-	PageOptions    map[string]interface{} `json:"page_options"`
-	PluginOptions  map[string]interface{} `json:"plugin_options"`
+	SpiderOptions SpiderOptions `json:"spider_options"`
+	PageOptions   PageOptions   `json:"page_options"`
+	PluginOptions PluginOptions `json:"plugin_options"`
 }
 
 // CrawlRequestList represents a paginated list of crawl requests
@@ -26,14 +28,18 @@ type CrawlRequestList struct {
 	Results  []CrawlRequest `json:"results"`
 }
 
-// CrawlResult represents a crawl result
+// CrawlResult represents a crawl result.
+// The page content is available through ResultData or Client.FetchResultData.
 type CrawlResult struct {
-	UUID       string                 `json:"uuid"`
-	URL        string                 `json:"url"`
-	Status     string                 `json:"status"`
-	Data       map[string]interface{} `json:"data"`
-	CreatedAt  string                 `json:"created_at"`
-	UpdatedAt  string                 `json:"updated_at"`
+	UUID        string                 `json:"uuid"`
+	URL         string                 `json:"url"`
+	Title       string                 `json:"title,omitempty"`
+	Status      string                 `json:"status"`
+	Data        map[string]interface{} `json:"data"`
+	Result      json.RawMessage        `json:"result,omitempty"` // Inline object or URL string
+	Attachments []Attachment           `json:"attachments,omitempty"`
+	CreatedAt   Timestamp              `json:"created_at"`
+	UpdatedAt   Timestamp              `json:"updated_at"`
 }
 
 // CrawlResultList represents a paginated list of crawl results
@@ -44,14 +50,23 @@ type CrawlResultList struct {
 	Results  []CrawlResult `json:"results"`
 }
 
-// EventStreamMessage represents a message from the event stream
+// EventStreamMessage represents a message from the event stream.
+// Use Event to decode it into a typed event.
 type EventStreamMessage struct {
-	Type string      `json:"type"`
+	Type EventType   `json:"type"`
 	Data interface{} `json:"data"`
+
+	// RawData is the event data exactly as received from the server
+	RawData json.RawMessage `json:"-"`
 }
 
 // CreateCrawlRequestInput represents the input for creating a crawl request
 type CreateCrawlRequestInput struct {
-	URL     interface{} `json:"url"` // Can be string or []string
+	URL     URLs         `json:"url"`
 	Options CrawlOptions `json:"options"`
-} 
\ No newline at end of file
+
+	// IdempotencyKey, when set, is sent as the Idempotency-Key header and
+	// allows the request to be retried under the client's retry policy.
+	// See NewIdempotencyKey.
+	IdempotencyKey string `json:"-"`
+}
@@ -0,0 +1,526 @@
+package watercrawl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// lastEventIDHeader carries the ID of the last received event when resuming a stream
+const lastEventIDHeader = "Last-Event-ID"
+
+// Errors reported by CrawlMonitor.Err
+var (
+	// ErrMonitorClosed is reported when monitoring was stopped with Close
+	ErrMonitorClosed = errors.New("watercrawl: monitor closed")
+	// ErrStreamUnavailable is reported when the event stream cannot be used
+	// and the polling fallback is disabled
+	ErrStreamUnavailable = errors.New("watercrawl: event stream unavailable")
+)
+
+// ReconnectPolicy controls how MonitorCrawlRequest recovers when the status
+// stream drops before the request reaches a terminal status
+type ReconnectPolicy struct {
+	// MaxAttempts is the number of consecutive failed reconnects after which
+	// monitoring falls back to polling. A stream that drops without
+	// delivering an event with a new ID counts as failed. 0 falls back
+	// immediately.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first reconnect, unless the
+	// stream requested another one with a retry field
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between reconnects
+	MaxBackoff time.Duration
+
+	// PollInterval is the interval between GetCrawlRequest calls once
+	// streaming is given up. 0 disables the polling fallback.
+	PollInterval time.Duration
+}
+
+// DefaultReconnectPolicy returns the reconnect policy used by default
+func DefaultReconnectPolicy() ReconnectPolicy {
+	return ReconnectPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		PollInterval:   2 * time.Second,
+	}
+}
+
+// WithReconnectPolicy sets how dropped status streams are resumed
+func WithReconnectPolicy(policy ReconnectPolicy) Option {
+	return func(c *Client) {
+		c.reconnectPolicy = policy
+	}
+}
+
+// backoff returns the delay before the given reconnect attempt
+func (p ReconnectPolicy) backoff(attempt int, serverRetry time.Duration) time.Duration {
+	initial := p.InitialBackoff
+	if serverRetry > 0 {
+		initial = serverRetry
+	}
+	return RetryPolicy{
+		InitialBackoff: initial,
+		MaxBackoff:     p.MaxBackoff,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}.backoff(attempt)
+}
+
+// MonitorOptions configures WatchCrawlRequest and WatchSitemapRequest
+type MonitorOptions struct {
+	// Download replaces the data of result events with the downloaded
+	// results. It only applies to crawl requests.
+	Download bool
+
+	// OnError, if set, is called with errors monitoring recovers from:
+	// dropped streams, undecodable events and failed downloads. It is called
+	// from the monitoring goroutine.
+	OnError func(err error)
+}
+
+// CrawlMonitor is a handle on the monitoring of a crawl or sitemap request
+type CrawlMonitor struct {
+	events chan *EventStreamMessage
+	done   chan struct{}
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	closed bool
+	err    error
+}
+
+// Events returns the channel of events. It is closed once the request reaches
+// a terminal status, monitoring fails, ctx is done or Close is called.
+func (m *CrawlMonitor) Events() <-chan *EventStreamMessage {
+	return m.events
+}
+
+// Err returns the error that ended monitoring once the events channel is
+// closed: nil if the request reached a terminal status, ErrMonitorClosed after
+// Close, the context error if ctx is done, or the error that could not be
+// recovered from. It returns nil while monitoring is running.
+func (m *CrawlMonitor) Err() error {
+	select {
+	case <-m.done:
+	default:
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.err
+}
+
+// Close stops monitoring and waits for it to end. Events not yet received are dropped.
+func (m *CrawlMonitor) Close() error {
+	m.mu.Lock()
+	m.closed = true
+	m.mu.Unlock()
+
+	m.cancel()
+	<-m.done
+	return nil
+}
+
+// finish records the error that ended monitoring
+func (m *CrawlMonitor) finish(err error) {
+	m.mu.Lock()
+	if m.closed && err != nil {
+		err = ErrMonitorClosed
+	}
+	m.err = err
+	m.mu.Unlock()
+
+	close(m.events)
+	close(m.done)
+}
+
+// WatchCrawlRequest monitors the status of a crawl request. Dropped streams,
+// and a first stream that fails to open with a transient error, are resumed
+// according to the client's reconnect policy; only permanent errors are
+// returned up front. opts may be nil.
+func (c *Client) WatchCrawlRequest(ctx context.Context, id string, opts *MonitorOptions) (*CrawlMonitor, error) {
+	if opts == nil {
+		opts = &MonitorOptions{}
+	}
+
+	monitor := &statusMonitor{
+		client:  c,
+		kind:    "crawl request",
+		id:      id,
+		path:    c.apiPath("core/crawl-requests/%s/status/", id),
+		onError: opts.OnError,
+		get: func(ctx context.Context) (interface{}, error) {
+			return c.GetCrawlRequest(ctx, id)
+		},
+	}
+	if opts.Download {
+		monitor.download = func(ctx context.Context) (map[string]interface{}, error) {
+			return c.DownloadCrawlRequest(ctx, id)
+		}
+	}
+
+	return c.watch(ctx, monitor)
+}
+
+// watch starts monitor in the background and returns its handle
+func (c *Client) watch(ctx context.Context, monitor *statusMonitor) (*CrawlMonitor, error) {
+	// The stream is opened with the monitor context so that Close interrupts it
+	ctx, cancel := context.WithCancel(ctx)
+
+	// Only permanent errors fail up front, transient ones are recovered from
+	// by reconnecting or polling like a dropped stream
+	resp, err := c.openEventStream(ctx, monitor.path, "")
+	if err != nil {
+		if ctx.Err() != nil || isPermanentError(err) {
+			cancel()
+			return nil, err
+		}
+		monitor.report(fmt.Errorf("opening event stream: %w", err))
+		resp = nil
+	}
+
+	handle := &CrawlMonitor{
+		events: make(chan *EventStreamMessage),
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+
+	go func() {
+		defer cancel()
+		err := monitor.run(ctx, resp, handle.events)
+		if err != nil && ctx.Err() == nil {
+			c.logger.Errorf("Monitoring %s %s stopped: %v", monitor.kind, monitor.id, err)
+		}
+		handle.finish(err)
+	}()
+
+	return handle, nil
+}
+
+// statusMonitor follows the status of a request, over the event stream
+// while possible and by polling otherwise
+type statusMonitor struct {
+	client  *Client
+	kind    string // e.g. "crawl request", for messages
+	id      string
+	path    string
+	onError func(err error)
+
+	// get fetches the request when polling
+	get func(ctx context.Context) (interface{}, error)
+	// download, if set, fetches the data replacing that of result events
+	download func(ctx context.Context) (map[string]interface{}, error)
+
+	lastEventID string
+	retry       time.Duration
+}
+
+// requestState is the part of a request that monitoring looks at
+type requestState struct {
+	Status   CrawlStatus `json:"status"`
+	Progress float64     `json:"progress"`
+}
+
+// openEventStream opens an event stream, resuming after lastEventID if set
+func (c *Client) openEventStream(ctx context.Context, path, lastEventID string) (*http.Response, error) {
+	var header http.Header
+	if lastEventID != "" {
+		header = http.Header{}
+		header.Set(lastEventIDHeader, lastEventID)
+	}
+
+	resp, err := c.doRequestWithHeader(ctx, http.MethodGet, path, nil, nil, header)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, c.processResponse(resp, nil)
+	}
+
+	return resp, nil
+}
+
+// run delivers events to out until the request reaches a terminal status, ctx
+// is done or monitoring fails. resp is the already opened first stream, nil
+// if opening it failed.
+func (m *statusMonitor) run(ctx context.Context, resp *http.Response, out chan<- *EventStreamMessage) error {
+	c := m.client
+	policy := c.reconnectPolicy
+	failures := 0
+
+	for {
+		if resp == nil {
+			if failures >= policy.MaxAttempts {
+				break
+			}
+
+			delay := policy.backoff(failures+1, m.retry)
+			c.logger.Infof("Event stream for %s %s dropped, reconnecting in %s", m.kind, m.id, delay)
+			if err := sleepContext(ctx, delay); err != nil {
+				return err
+			}
+
+			var err error
+			resp, err = c.openEventStream(ctx, m.path, m.lastEventID)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				if isPermanentError(err) {
+					return err
+				}
+				m.report(fmt.Errorf("reconnecting to event stream: %w", err))
+				failures++
+				continue
+			}
+		}
+
+		if !isEventStream(resp) {
+			m.report(fmt.Errorf("status endpoint returned %q instead of an event stream", resp.Header.Get("Content-Type")))
+			c.closeBody(resp)
+			break
+		}
+
+		lastEventID := m.lastEventID
+		terminal, err := m.consume(ctx, resp, out)
+		resp = nil
+		if terminal {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		m.report(fmt.Errorf("reading event stream: %w", err))
+
+		// Only events with a new ID are progress: a stream replaying the
+		// same events, or sending none with an ID, before dropping counts
+		// as a failed attempt
+		if m.lastEventID != lastEventID {
+			failures = 0
+		} else {
+			failures++
+		}
+	}
+
+	return m.poll(ctx, out)
+}
+
+// consume reads events from one stream until it ends. It reports whether
+// the request reached a terminal status.
+func (m *statusMonitor) consume(ctx context.Context, resp *http.Response, out chan<- *EventStreamMessage) (bool, error) {
+	c := m.client
+	defer c.closeBody(resp)
+
+	// The last event ID carries over reconnects, as with EventSource
+	decoder := NewSSEDecoder(resp.Body)
+	decoder.lastEventID = m.lastEventID
+
+	for {
+		sse, err := decoder.Next()
+		m.lastEventID = decoder.LastEventID()
+		if retry := decoder.Retry(); retry > 0 {
+			m.retry = retry
+		}
+		if err != nil {
+			return false, err
+		}
+
+		c.logger.Debugf("Received %s event (id %q): %s", sse.Event, sse.ID, sse.Data)
+
+		// Parse the JSON payload
+		var event EventStreamMessage
+		if err := json.Unmarshal([]byte(sse.Data), &event); err != nil {
+			m.report(fmt.Errorf("decoding %s event: %w", sse.Event, err))
+			continue
+		}
+		if event.Type == "" && sse.Event != "message" {
+			event.Type = EventType(sse.Event)
+		}
+
+		m.downloadResult(ctx, &event)
+
+		if err := m.send(ctx, &event, out); err != nil {
+			return false, err
+		}
+
+		if isTerminalEvent(&event) {
+			return true, nil
+		}
+	}
+}
+
+// poll follows the request by fetching it, delivering a state event
+// whenever its status or progress changes
+func (m *statusMonitor) poll(ctx context.Context, out chan<- *EventStreamMessage) error {
+	c := m.client
+	interval := c.reconnectPolicy.PollInterval
+	if interval <= 0 {
+		return ErrStreamUnavailable
+	}
+
+	c.logger.Infof("Falling back to polling %s %s every %s", m.kind, m.id, interval)
+
+	var last *requestState
+	for {
+		request, err := m.get(ctx)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil && isPermanentError(err):
+			return err
+		case err != nil:
+			m.report(fmt.Errorf("polling %s: %w", m.kind, err))
+		default:
+			event, err := stateEvent(request)
+			if err != nil {
+				return err
+			}
+			state, err := eventState(event)
+			if err != nil {
+				return err
+			}
+			if last != nil && *last == state {
+				break
+			}
+
+			last = &state
+			if err := m.send(ctx, event, out); err != nil {
+				return err
+			}
+			if state.Status.IsTerminal() {
+				return nil
+			}
+		}
+
+		if err := sleepContext(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
+
+// downloadResult replaces the data of a result event with the downloaded results if requested
+func (m *statusMonitor) downloadResult(ctx context.Context, event *EventStreamMessage) {
+	if m.download == nil || event.Type != EventTypeResult {
+		return
+	}
+	if _, ok := event.Data.(map[string]interface{}); !ok {
+		return
+	}
+
+	c := m.client
+
+	// Bound the download, Close and ctx still interrupt it
+	downloadCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	downloadedData, err := m.download(downloadCtx)
+	cancel()
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		m.report(fmt.Errorf("downloading results: %w", err))
+		return
+	}
+
+	// Replace the entire event data with downloaded data
+	event.Data = downloadedData
+	c.logger.Debugf("Successfully downloaded result data")
+}
+
+// report logs an error monitoring recovers from and passes it to the OnError callback
+func (m *statusMonitor) report(err error) {
+	m.client.logger.Warnf("Monitoring %s %s: %v", m.kind, m.id, err)
+	if m.onError != nil {
+		m.onError(err)
+	}
+}
+
+// send delivers an event, respecting context cancellation
+func (m *statusMonitor) send(ctx context.Context, event *EventStreamMessage, out chan<- *EventStreamMessage) error {
+	select {
+	case out <- event:
+		return nil
+	case <-ctx.Done():
+		m.client.logger.Debugf("Context done while sending event")
+		return ctx.Err()
+	}
+}
+
+// stateEvent builds the state event the stream would have sent for request
+func stateEvent(request interface{}) (*EventStreamMessage, error) {
+	raw, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal request: %w", err)
+	}
+
+	return &EventStreamMessage{
+		Type:    EventTypeState,
+		Data:    data,
+		RawData: raw,
+	}, nil
+}
+
+// eventState decodes the status and progress carried by a state event
+func eventState(event *EventStreamMessage) (requestState, error) {
+	raw := event.RawData
+	if len(raw) == 0 {
+		var err error
+		if raw, err = json.Marshal(event.Data); err != nil {
+			return requestState{}, fmt.Errorf("failed to marshal event data: %w", err)
+		}
+	}
+
+	var state requestState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return requestState{}, fmt.Errorf("failed to decode state event: %w", err)
+	}
+	return state, nil
+}
+
+// isTerminalEvent reports whether the event says the request has stopped
+func isTerminalEvent(event *EventStreamMessage) bool {
+	switch event.Type {
+	case EventTypeCompleted:
+		return true
+	case EventTypeState:
+		state, err := eventState(event)
+		return err == nil && state.Status.IsTerminal()
+	}
+	return false
+}
+
+// isEventStream reports whether the response is a text/event-stream
+func isEventStream(resp *http.Response) bool {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return err == nil && mediaType == "text/event-stream"
+}
+
+// isPermanentError reports whether retrying the request cannot succeed
+func isPermanentError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 &&
+		apiErr.StatusCode != http.StatusRequestTimeout && apiErr.StatusCode != http.StatusTooManyRequests
+}
@@ -0,0 +1,530 @@
+package watercrawl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testReconnectPolicy() ReconnectPolicy {
+	return ReconnectPolicy{
+		MaxAttempts:    2,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		PollInterval:   time.Millisecond,
+	}
+}
+
+// writeStateEvent writes a state event with the given SSE id and crawl status
+func writeStateEvent(t *testing.T, w http.ResponseWriter, id string, status CrawlStatus) {
+	data, err := json.Marshal(map[string]interface{}{
+		"type": "state",
+		"data": map[string]interface{}{"uuid": "test-uuid", "status": status},
+	})
+	if err != nil {
+		t.Errorf("Failed to marshal state event: %v", err)
+	}
+	if id != "" {
+		fmt.Fprintf(w, "id: %s\n", id)
+	}
+	fmt.Fprintf(w, "data: %s\n\n", data)
+}
+
+// collectEvents reads events until the channel is closed
+func collectEvents(t *testing.T, events <-chan *EventStreamMessage) []*EventStreamMessage {
+	t.Helper()
+	var got []*EventStreamMessage
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return got
+			}
+			got = append(got, event)
+		case <-timeout:
+			t.Fatalf("event channel not closed, got %d events", len(got))
+		}
+	}
+}
+
+func eventStatuses(t *testing.T, events []*EventStreamMessage) []CrawlStatus {
+	var statuses []CrawlStatus
+	for _, event := range events {
+		typed, err := event.Event()
+		if err != nil {
+			t.Fatalf("Event() error = %v", err)
+		}
+		if state, ok := typed.(StateEvent); ok {
+			statuses = append(statuses, state.Request.Status)
+		}
+	}
+	return statuses
+}
+
+func TestClient_MonitorCrawlRequest_Reconnect(t *testing.T) {
+	var connects int32
+	var lastEventID atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		switch atomic.AddInt32(&connects, 1) {
+		case 1:
+			writeStateEvent(t, w, "1", CrawlStatusRunning)
+		case 2:
+			// Dropped before any event
+		default:
+			lastEventID.Store(r.Header.Get("Last-Event-ID"))
+			writeStateEvent(t, w, "2", CrawlStatusFinished)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/", WithReconnectPolicy(testReconnectPolicy()))
+
+	events, err := client.MonitorCrawlRequest(context.Background(), "test-uuid", false)
+	if err != nil {
+		t.Fatalf("MonitorCrawlRequest() error = %v", err)
+	}
+
+	got := eventStatuses(t, collectEvents(t, events))
+	want := []CrawlStatus{CrawlStatusRunning, CrawlStatusFinished}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("statuses = %v, want %v", got, want)
+	}
+	if got := atomic.LoadInt32(&connects); got != 3 {
+		t.Errorf("connects = %d, want 3", got)
+	}
+	if got, _ := lastEventID.Load().(string); got != "1" {
+		t.Errorf("Last-Event-ID = %q, want %q", got, "1")
+	}
+}
+
+func TestClient_MonitorCrawlRequest_PollingFallback(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream http.HandlerFunc
+	}{
+		{
+			name: "not an event stream",
+			stream: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, "{}")
+			},
+		},
+		{
+			name: "reconnects exhausted",
+			stream: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var polls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/api/v1/core/crawl-requests/test-uuid/status/" {
+					tt.stream(w, r)
+					return
+				}
+
+				status := CrawlStatusRunning
+				if atomic.AddInt32(&polls, 1) >= 3 {
+					status = CrawlStatusFinished
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if err := json.NewEncoder(w).Encode(CrawlRequest{UUID: "test-uuid", Status: status}); err != nil {
+					t.Errorf("Failed to encode response: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient("test-key", server.URL+"/", WithReconnectPolicy(testReconnectPolicy()))
+
+			events, err := client.MonitorCrawlRequest(context.Background(), "test-uuid", false)
+			if err != nil {
+				t.Fatalf("MonitorCrawlRequest() error = %v", err)
+			}
+
+			// Unchanged polls are not repeated
+			got := eventStatuses(t, collectEvents(t, events))
+			want := []CrawlStatus{CrawlStatusRunning, CrawlStatusFinished}
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("statuses = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestClient_MonitorCrawlRequest_ReplayingStream(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxAttempts  int
+		id           string
+		wantConnects int32
+	}{
+		{name: "no fallback attempts", maxAttempts: 0, id: "1", wantConnects: 1},
+		{name: "same event ID", maxAttempts: 2, id: "1", wantConnects: 3},
+		{name: "no event ID", maxAttempts: 2, id: "", wantConnects: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var connects int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/api/v1/core/crawl-requests/test-uuid/status/" {
+					// Each connection replays the same event, then drops
+					atomic.AddInt32(&connects, 1)
+					w.Header().Set("Content-Type", "text/event-stream")
+					writeStateEvent(t, w, tt.id, CrawlStatusRunning)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if err := json.NewEncoder(w).Encode(CrawlRequest{UUID: "test-uuid", Status: CrawlStatusFinished}); err != nil {
+					t.Errorf("Failed to encode response: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			policy := testReconnectPolicy()
+			policy.MaxAttempts = tt.maxAttempts
+			client := NewClient("test-key", server.URL+"/", WithReconnectPolicy(policy))
+
+			events, err := client.MonitorCrawlRequest(context.Background(), "test-uuid", false)
+			if err != nil {
+				t.Fatalf("MonitorCrawlRequest() error = %v", err)
+			}
+
+			statuses := eventStatuses(t, collectEvents(t, events))
+			if len(statuses) == 0 || statuses[len(statuses)-1] != CrawlStatusFinished {
+				t.Errorf("statuses = %v, want polling to report %s", statuses, CrawlStatusFinished)
+			}
+			if got := atomic.LoadInt32(&connects); got != tt.wantConnects {
+				t.Errorf("connects = %d, want %d", got, tt.wantConnects)
+			}
+		})
+	}
+}
+
+func TestClient_MonitorCrawlRequest_PermanentError(t *testing.T) {
+	var connects int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&connects, 1) == 1 {
+			w.Header().Set("Content-Type", "text/event-stream")
+			writeStateEvent(t, w, "1", CrawlStatusRunning)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/", WithReconnectPolicy(testReconnectPolicy()))
+
+	events, err := client.MonitorCrawlRequest(context.Background(), "test-uuid", false)
+	if err != nil {
+		t.Fatalf("MonitorCrawlRequest() error = %v", err)
+	}
+
+	if got := collectEvents(t, events); len(got) != 1 {
+		t.Errorf("events = %d, want 1", len(got))
+	}
+	if got := atomic.LoadInt32(&connects); got != 2 {
+		t.Errorf("connects = %d, want 2", got)
+	}
+}
+
+func TestClient_MonitorCrawlRequest_InitialError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	if _, err := client.MonitorCrawlRequest(context.Background(), "test-uuid", false); err == nil {
+		t.Errorf("MonitorCrawlRequest() error = nil, want error")
+	}
+}
+
+func TestClient_MonitorCrawlRequest_InitialTransientError(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts int
+		wantStream  bool
+	}{
+		{name: "reconnect", maxAttempts: 2, wantStream: true},
+		{name: "polling", maxAttempts: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var connects int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/api/v1/core/crawl-requests/test-uuid/status/" {
+					if atomic.AddInt32(&connects, 1) == 1 {
+						w.WriteHeader(http.StatusBadGateway)
+						return
+					}
+					w.Header().Set("Content-Type", "text/event-stream")
+					writeStateEvent(t, w, "1", CrawlStatusFinished)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if err := json.NewEncoder(w).Encode(CrawlRequest{UUID: "test-uuid", Status: CrawlStatusFinished}); err != nil {
+					t.Errorf("Failed to encode response: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			policy := testReconnectPolicy()
+			policy.MaxAttempts = tt.maxAttempts
+			client := NewClient("test-key", server.URL+"/", WithReconnectPolicy(policy))
+
+			var reported []error
+			monitor, err := client.WatchCrawlRequest(context.Background(), "test-uuid", &MonitorOptions{
+				OnError: func(err error) { reported = append(reported, err) },
+			})
+			if err != nil {
+				t.Fatalf("WatchCrawlRequest() error = %v", err)
+			}
+
+			got := eventStatuses(t, collectEvents(t, monitor.Events()))
+			if fmt.Sprint(got) != fmt.Sprint([]CrawlStatus{CrawlStatusFinished}) {
+				t.Errorf("statuses = %v, want [%s]", got, CrawlStatusFinished)
+			}
+			if err := monitor.Err(); err != nil {
+				t.Errorf("Err() = %v, want nil", err)
+			}
+			if len(reported) == 0 {
+				t.Error("OnError not called for the failed stream")
+			}
+			if wantConnects := map[bool]int32{true: 2, false: 1}[tt.wantStream]; atomic.LoadInt32(&connects) != wantConnects {
+				t.Errorf("connects = %d, want %d", atomic.LoadInt32(&connects), wantConnects)
+			}
+		})
+	}
+}
+
+func TestClient_MonitorCrawlRequest_ContextCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		writeStateEvent(t, w, "", CrawlStatusRunning)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/", WithReconnectPolicy(testReconnectPolicy()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := client.MonitorCrawlRequest(ctx, "test-uuid", false)
+	if err != nil {
+		t.Fatalf("MonitorCrawlRequest() error = %v", err)
+	}
+
+	<-events
+	cancel()
+	collectEvents(t, events)
+}
+
+func TestClient_WatchCrawlRequest_Err(t *testing.T) {
+	noPolling := testReconnectPolicy()
+	noPolling.PollInterval = 0
+
+	tests := []struct {
+		name    string
+		policy  ReconnectPolicy
+		handler http.HandlerFunc
+		wantErr error
+	}{
+		{
+			name:   "terminal status",
+			policy: testReconnectPolicy(),
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				writeStateEvent(t, w, "", CrawlStatusFinished)
+			},
+			wantErr: nil,
+		},
+		{
+			name:   "stream unavailable",
+			policy: noPolling,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, "{}")
+			},
+			wantErr: ErrStreamUnavailable,
+		},
+		{
+			name:   "permanent error",
+			policy: noPolling,
+			handler: func() http.HandlerFunc {
+				var connects int32
+				return func(w http.ResponseWriter, r *http.Request) {
+					if atomic.AddInt32(&connects, 1) > 1 {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+					w.Header().Set("Content-Type", "text/event-stream")
+				}
+			}(),
+			wantErr: ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			client := NewClient("test-key", server.URL+"/", WithReconnectPolicy(tt.policy))
+
+			monitor, err := client.WatchCrawlRequest(context.Background(), "test-uuid", nil)
+			if err != nil {
+				t.Fatalf("WatchCrawlRequest() error = %v", err)
+			}
+
+			collectEvents(t, monitor.Events())
+			if err := monitor.Err(); !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Errorf("Err() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_WatchCrawlRequest_Close(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		writeStateEvent(t, w, "", CrawlStatusRunning)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	monitor, err := client.WatchCrawlRequest(context.Background(), "test-uuid", nil)
+	if err != nil {
+		t.Fatalf("WatchCrawlRequest() error = %v", err)
+	}
+
+	<-monitor.Events()
+	if err := monitor.Err(); err != nil {
+		t.Errorf("Err() while running = %v, want nil", err)
+	}
+	if err := monitor.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if _, ok := <-monitor.Events(); ok {
+		t.Errorf("Events() not closed after Close()")
+	}
+	if err := monitor.Err(); !errors.Is(err, ErrMonitorClosed) {
+		t.Errorf("Err() = %v, want %v", err, ErrMonitorClosed)
+	}
+}
+
+func TestClient_WatchCrawlRequest_CloseDuringDownload(t *testing.T) {
+	downloading := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/core/crawl-requests/test-uuid/status/":
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"type\":\"result\",\"data\":{\"url\":\"https://example.com\"}}\n\n")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		case "/api/v1/core/crawl-requests/test-uuid/download/":
+			downloading <- struct{}{}
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	monitor, err := client.WatchCrawlRequest(context.Background(), "test-uuid", &MonitorOptions{Download: true})
+	if err != nil {
+		t.Fatalf("WatchCrawlRequest() error = %v", err)
+	}
+
+	select {
+	case <-downloading:
+	case <-time.After(2 * time.Second):
+		t.Fatal("result download not started")
+	}
+
+	start := time.Now()
+	monitor.Close()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Close() took %s, want the download interrupted", elapsed)
+	}
+	if err := monitor.Err(); !errors.Is(err, ErrMonitorClosed) {
+		t.Errorf("Err() = %v, want %v", err, ErrMonitorClosed)
+	}
+}
+
+func TestClient_WatchCrawlRequest_ContextDone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	monitor, err := client.WatchCrawlRequest(ctx, "test-uuid", nil)
+	if err != nil {
+		t.Fatalf("WatchCrawlRequest() error = %v", err)
+	}
+
+	collectEvents(t, monitor.Events())
+	if err := monitor.Err(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Err() = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestClient_WatchCrawlRequest_OnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {not json\n\n")
+		writeStateEvent(t, w, "", CrawlStatusFinished)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	var mu sync.Mutex
+	var reported []error
+	monitor, err := client.WatchCrawlRequest(context.Background(), "test-uuid", &MonitorOptions{
+		OnError: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			reported = append(reported, err)
+		},
+	})
+	if err != nil {
+		t.Fatalf("WatchCrawlRequest() error = %v", err)
+	}
+
+	if got := collectEvents(t, monitor.Events()); len(got) != 1 {
+		t.Errorf("events = %d, want 1", len(got))
+	}
+	if err := monitor.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var syntaxErr *json.SyntaxError
+	if len(reported) != 1 || !errors.As(reported[0], &syntaxErr) {
+		t.Errorf("reported errors = %v, want one JSON syntax error", reported)
+	}
+}
@@ -0,0 +1,89 @@
+package watercrawl
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used to perform requests.
+// Use it to plug in a custom transport, proxy settings or instrumentation.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithTimeout bounds how long each attempt of an HTTP request waits for the
+// response headers. It does not bound reading the response body, so status
+// event streams and downloads may run longer; use the request context to
+// limit them. An attempt that times out fails with a *TimeoutError.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithBaseURL overrides the base URL of the WaterCrawl API.
+// A path on the base URL is kept as a prefix of every request path,
+// e.g. "https://internal.example.com/watercrawl/".
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		if baseURL != "" {
+			c.baseURL = baseURL
+		}
+	}
+}
+
+// WithAPIVersion overrides the API version segment of request paths
+// ("/api/<version>/..."), for self-hosted deployments running another version
+func WithAPIVersion(version string) Option {
+	return func(c *Client) {
+		version = strings.Trim(version, "/")
+		if version != "" {
+			c.apiVersion = version
+		}
+	}
+}
+
+// WithUserAgent overrides the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		if userAgent != "" {
+			c.userAgent = userAgent
+		}
+	}
+}
+
+// WithAcceptLanguage overrides the Accept-Language header sent with every request
+func WithAcceptLanguage(language string) Option {
+	return func(c *Client) {
+		if language != "" {
+			c.acceptLanguage = language
+		}
+	}
+}
+
+// WithHeader adds a header sent with every request.
+// Headers managed by the client (X-API-Key, Content-Type, Accept, User-Agent,
+// Accept-Language) cannot be overridden this way; use the dedicated options instead.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.headers.Add(key, value)
+	}
+}
+
+// WithLogger sets the logger used for diagnostic output.
+// By default the client is silent.
+func WithLogger(logger Logger) Option {
+	return func(c *Client) {
+		if logger != nil {
+			c.logger = logger
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package watercrawl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewClient_Options(t *testing.T) {
+	httpClient := &http.Client{}
+	client := NewClient("test-key", "",
+		WithHTTPClient(httpClient),
+		WithTimeout(5*time.Second),
+		WithBaseURL("https://custom.example.com/"),
+		WithUserAgent("my-service/1.0"),
+		WithAcceptLanguage("de-DE"),
+		WithHeader("X-Trace", "abc"),
+	)
+
+	if client.baseURL != "https://custom.example.com/" {
+		t.Errorf("baseURL = %v, want %v", client.baseURL, "https://custom.example.com/")
+	}
+	if client.httpClient != httpClient {
+		t.Error("httpClient is not the client passed to WithHTTPClient")
+	}
+	if httpClient.Timeout != 0 {
+		t.Errorf("httpClient.Timeout = %v, want 0", httpClient.Timeout)
+	}
+	if client.timeout != 5*time.Second {
+		t.Errorf("timeout = %v, want %v", client.timeout, 5*time.Second)
+	}
+	if client.userAgent != "my-service/1.0" {
+		t.Errorf("userAgent = %v, want %v", client.userAgent, "my-service/1.0")
+	}
+}
+
+func TestClient_doRequest_Options(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if userAgent := r.Header.Get("User-Agent"); userAgent != "my-service/1.0" {
+			t.Errorf("Expected User-Agent header to be 'my-service/1.0', got %v", userAgent)
+		}
+		if language := r.Header.Get("Accept-Language"); language != "de-DE" {
+			t.Errorf("Expected Accept-Language header to be 'de-DE', got %v", language)
+		}
+		if trace := r.Header.Get("X-Trace"); trace != "abc" {
+			t.Errorf("Expected X-Trace header to be 'abc', got %v", trace)
+		}
+		if apiKey := r.Header.Get("X-API-Key"); apiKey != "test-key" {
+			t.Errorf("Expected X-API-Key header to be 'test-key', got %v", apiKey)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	var transportCalls int
+	httpClient := &http.Client{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			transportCalls++
+			return http.DefaultTransport.RoundTrip(r)
+		}),
+	}
+
+	client := NewClient("test-key", "https://unused.example.com/",
+		WithBaseURL(server.URL+"/"),
+		WithHTTPClient(httpClient),
+		WithUserAgent("my-service/1.0"),
+		WithAcceptLanguage("de-DE"),
+		WithHeader("X-Trace", "abc"),
+		WithHeader("X-API-Key", "override"),
+	)
+
+	resp, err := client.doRequest(context.Background(), http.MethodGet, "/test", nil, nil)
+	if err != nil {
+		t.Fatalf("doRequest() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if transportCalls != 1 {
+		t.Errorf("custom transport calls = %d, want 1", transportCalls)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		headerDelay time.Duration
+		bodyDelay   time.Duration
+		wantTimeout bool
+	}{
+		{name: "slow body", bodyDelay: 300 * time.Millisecond},
+		{name: "slow headers", headerDelay: 300 * time.Millisecond, wantTimeout: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(tt.headerDelay)
+				w.Header().Set("Content-Type", "application/octet-stream")
+				w.WriteHeader(http.StatusOK)
+				w.(http.Flusher).Flush()
+				time.Sleep(tt.bodyDelay)
+				fmt.Fprint(w, "content")
+			}))
+			defer server.Close()
+
+			client := NewClient("test-key", server.URL+"/", WithTimeout(100*time.Millisecond))
+
+			var buf bytes.Buffer
+			_, err := client.DownloadCrawlRequestTo(context.Background(), "test-uuid", &buf)
+			var timeoutErr *TimeoutError
+			if tt.wantTimeout {
+				if !errors.As(err, &timeoutErr) || !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("DownloadCrawlRequestTo() error = %v, want *TimeoutError", err)
+				}
+				return
+			}
+			if err != nil || buf.String() != "content" {
+				t.Errorf("DownloadCrawlRequestTo() = %q, %v, want content", buf.String(), err)
+			}
+		})
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,65 @@
+package watercrawl
+
+import (
+	"context"
+	"time"
+)
+
+// stopTimeout bounds the StopCrawlRequest call made for an abandoned crawl
+const stopTimeout = 10 * time.Second
+
+// WithStopAbandonedCrawls sets whether crawl requests owned by the client
+// are stopped when their caller abandons them, which is the default. Owned
+// crawl requests are those created by Scrape, ScrapeURL, ScrapeMany,
+// BatchScrape and RunOwnedCrawl.
+func WithStopAbandonedCrawls(stop bool) Option {
+	return func(c *Client) {
+		c.keepAbandonedCrawls = !stop
+	}
+}
+
+// RunOwnedCrawl creates a crawl request and calls fn with it. The crawl
+// request is owned by the call: if fn fails once ctx is done, the crawl
+// request is stopped with StopCrawlRequest, unless disabled with
+// WithStopAbandonedCrawls, and an *AbandonedCrawlError is returned.
+func (c *Client) RunOwnedCrawl(ctx context.Context, input CreateCrawlRequestInput, fn func(ctx context.Context, request *CrawlRequest) error) error {
+	request, err := c.CreateCrawlRequest(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	return c.ownCrawl(ctx, request.UUID, func() error {
+		return fn(ctx, request)
+	})
+}
+
+// ownCrawl calls fn and abandons the crawl request id if fn fails once ctx is done
+func (c *Client) ownCrawl(ctx context.Context, id string, fn func() error) error {
+	err := fn()
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+	return c.abandonCrawl(id, err)
+}
+
+// abandonCrawl stops a crawl request nobody waits for anymore and returns
+// the *AbandonedCrawlError reporting it. It uses a detached context since
+// the caller's one is typically done.
+func (c *Client) abandonCrawl(id string, cause error) error {
+	abandoned := &AbandonedCrawlError{CrawlRequest: id, Err: cause}
+	if c.keepAbandonedCrawls {
+		c.logger.Debugf("Leaving abandoned crawl request %s running", id)
+		return abandoned
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+	defer cancel()
+
+	c.logger.Infof("Stopping abandoned crawl request %s", id)
+	abandoned.StopErr = c.StopCrawlRequest(ctx, id)
+	abandoned.Stopped = abandoned.StopErr == nil
+	if abandoned.StopErr != nil {
+		c.logger.Warnf("Failed to stop abandoned crawl request %s: %v", id, abandoned.StopErr)
+	}
+	return abandoned
+}
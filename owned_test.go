@@ -0,0 +1,145 @@
+package watercrawl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_RunOwnedCrawl(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []Option
+		cancel      bool
+		stopStatus  int
+		wantStopped bool
+		wantStopErr bool
+		wantDeletes int
+	}{
+		{name: "finished", stopStatus: http.StatusNoContent},
+		{name: "abandoned", cancel: true, stopStatus: http.StatusNoContent, wantStopped: true, wantDeletes: 1},
+		{name: "stop fails", cancel: true, stopStatus: http.StatusForbidden, wantStopErr: true, wantDeletes: 1},
+		{name: "kept", opts: []Option{WithStopAbandonedCrawls(false)}, cancel: true, stopStatus: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var deletes []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case http.MethodPost:
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"uuid":"owned-crawl","status":"new"}`))
+				case http.MethodDelete:
+					mu.Lock()
+					deletes = append(deletes, strings.TrimPrefix(r.URL.Path, "/api/v1/core/crawl-requests/"))
+					mu.Unlock()
+					w.WriteHeader(tt.stopStatus)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient("test-key", server.URL+"/", tt.opts...)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			err := client.RunOwnedCrawl(ctx, CreateCrawlRequestInput{URL: URLs{"https://example.com"}}, func(ctx context.Context, request *CrawlRequest) error {
+				if request.UUID != "owned-crawl" {
+					t.Errorf("request.UUID = %q, want %q", request.UUID, "owned-crawl")
+				}
+				if !tt.cancel {
+					return nil
+				}
+				time.AfterFunc(10*time.Millisecond, cancel)
+				<-ctx.Done()
+				return ctx.Err()
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(deletes) != tt.wantDeletes {
+				t.Errorf("stop requests = %v, want %d", deletes, tt.wantDeletes)
+			}
+
+			if !tt.cancel {
+				if err != nil {
+					t.Errorf("RunOwnedCrawl() error = %v", err)
+				}
+				return
+			}
+
+			var abandoned *AbandonedCrawlError
+			if !errors.As(err, &abandoned) {
+				t.Fatalf("RunOwnedCrawl() error = %v, want *AbandonedCrawlError", err)
+			}
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("RunOwnedCrawl() error = %v, want %v", err, context.Canceled)
+			}
+			if abandoned.CrawlRequest != "owned-crawl" {
+				t.Errorf("CrawlRequest = %q, want %q", abandoned.CrawlRequest, "owned-crawl")
+			}
+			if abandoned.Stopped != tt.wantStopped {
+				t.Errorf("Stopped = %v, want %v", abandoned.Stopped, tt.wantStopped)
+			}
+			if (abandoned.StopErr != nil) != tt.wantStopErr {
+				t.Errorf("StopErr = %v, want error %v", abandoned.StopErr, tt.wantStopErr)
+			}
+		})
+	}
+}
+
+func TestClient_RunOwnedCrawl_CreateError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	called := false
+	err := client.RunOwnedCrawl(context.Background(), CreateCrawlRequestInput{URL: URLs{"https://example.com"}}, func(context.Context, *CrawlRequest) error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("RunOwnedCrawl() error = %v, want %v", err, ErrUnauthorized)
+	}
+	if called {
+		t.Error("RunOwnedCrawl() called fn without a crawl request")
+	}
+}
+
+func TestClient_Scrape_Canceled(t *testing.T) {
+	server := newScrapePoolServer(t)
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := client.ScrapeURL(ctx, "https://example.com/hang", nil, nil, true, false)
+
+	var abandoned *AbandonedCrawlError
+	if !errors.As(err, &abandoned) || !abandoned.Stopped {
+		t.Fatalf("ScrapeURL() error = %v, want stopped *AbandonedCrawlError", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ScrapeURL() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.stopped) != 1 || server.stopped[0] != abandoned.CrawlRequest {
+		t.Errorf("stopped crawl requests = %v, want [%s]", server.stopped, abandoned.CrawlRequest)
+	}
+}
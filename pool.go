@@ -0,0 +1,141 @@
+package watercrawl
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultScrapeWorkers is the number of workers ScrapeMany uses when none is set
+const DefaultScrapeWorkers = 4
+
+// ScrapeManyOptions configures ScrapeMany
+type ScrapeManyOptions struct {
+	// Workers is the number of URLs scraped concurrently, and so the maximum
+	// number of crawl requests in flight. DefaultScrapeWorkers if 0.
+	Workers int
+
+	// JobTimeout bounds the scrape of each URL, 0 for no timeout
+	JobTimeout time.Duration
+
+	// Ordered delivers results in the order of the URLs instead of as they complete
+	Ordered bool
+
+	// ScrapeOptions applies to every URL, may be nil
+	ScrapeOptions *ScrapeOptions
+}
+
+// ScrapeManyResult is the outcome of one URL of ScrapeMany
+type ScrapeManyResult struct {
+	// Index is the position of the URL in the list passed to ScrapeMany
+	Index int
+	URL   string
+
+	// Result is the scrape result, nil if Err is set
+	Result *ScrapeResult
+	Err    error
+}
+
+// ScrapeMany scrapes urls concurrently and delivers one result per URL on
+// the returned channel, which is closed once all URLs are done. The channel
+// must be drained.
+//
+// Requests made by the workers share the client's rate limiter and
+// concurrency limit. When ctx ends or a job times out, the crawl requests
+// still running are abandoned as described in RunOwnedCrawl and the URLs
+// not yet started fail with the context error. opts may be nil.
+func (c *Client) ScrapeMany(ctx context.Context, urls []string, opts *ScrapeManyOptions) <-chan ScrapeManyResult {
+	if opts == nil {
+		opts = &ScrapeManyOptions{}
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = DefaultScrapeWorkers
+	}
+	if workers > len(urls) {
+		workers = len(urls)
+	}
+
+	jobs := make(chan int)
+	done := make(chan ScrapeManyResult)
+	out := make(chan ScrapeManyResult)
+
+	go func() {
+		defer close(jobs)
+		for i := range urls {
+			jobs <- i
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result, err := c.scrapeJob(ctx, urls[i], opts)
+				done <- ScrapeManyResult{Index: i, URL: urls[i], Result: result, Err: err}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	go func() {
+		defer close(out)
+		if !opts.Ordered {
+			for result := range done {
+				out <- result
+			}
+			return
+		}
+
+		// Hold results back until those of the preceding URLs are delivered
+		pending := map[int]ScrapeManyResult{}
+		next := 0
+		for result := range done {
+			pending[result.Index] = result
+			for {
+				result, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				out <- result
+				next++
+			}
+		}
+	}()
+
+	return out
+}
+
+// scrapeJob scrapes one URL of ScrapeMany
+func (c *Client) scrapeJob(ctx context.Context, url string, opts *ScrapeManyOptions) (*ScrapeResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if opts.JobTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.JobTimeout)
+		defer cancel()
+	}
+
+	scrapeOpts := opts.ScrapeOptions
+	if scrapeOpts == nil {
+		scrapeOpts = &ScrapeOptions{}
+	}
+
+	request, err := c.createScrape(ctx, url, scrapeOpts)
+	if err != nil {
+		return nil, err
+	}
+	if scrapeOpts.Async {
+		return asyncScrapeResult(request), nil
+	}
+
+	return c.awaitOwnedScrape(ctx, request, scrapeOpts.Download)
+}
@@ -0,0 +1,234 @@
+package watercrawl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// scrapePoolServer fakes the crawl requests of single URL scrapes. The status
+// stream of a URL with a "sleep" query parameter is delayed by that many
+// milliseconds, and that of a URL with a "hang" path never ends.
+type scrapePoolServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	urls      map[string]string // crawl request UUID to URL
+	active    int
+	maxActive int
+	stopped   []string
+}
+
+func newScrapePoolServer(t *testing.T) *scrapePoolServer {
+	s := &scrapePoolServer{urls: map[string]string{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/api/v1/core/crawl-requests/")
+
+		switch {
+		case path == "" && r.Method == http.MethodPost:
+			var input struct {
+				URL URLs `json:"url"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+				t.Errorf("Failed to decode request body: %v", err)
+			}
+			s.mu.Lock()
+			id := fmt.Sprintf("crawl-%d", len(s.urls))
+			s.urls[id] = input.URL[0]
+			s.mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"uuid":%q,"status":"new"}`, id)
+		case strings.HasSuffix(path, "/status/"):
+			id := strings.TrimSuffix(path, "/status/")
+			s.mu.Lock()
+			target, _ := url.Parse(s.urls[id])
+			s.active++
+			if s.active > s.maxActive {
+				s.maxActive = s.active
+			}
+			s.mu.Unlock()
+			defer func() {
+				s.mu.Lock()
+				s.active--
+				s.mu.Unlock()
+			}()
+
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.(http.Flusher).Flush()
+			if target.Path == "/hang" {
+				<-r.Context().Done()
+				return
+			}
+			if ms, err := strconv.Atoi(target.Query().Get("sleep")); err == nil {
+				time.Sleep(time.Duration(ms) * time.Millisecond)
+			}
+			fmt.Fprintf(w, "data: {\"type\":\"result\",\"data\":{\"uuid\":%q,\"url\":%q}}\n\n", "result-"+id, target.String())
+		case r.Method == http.MethodDelete:
+			s.mu.Lock()
+			s.stopped = append(s.stopped, strings.TrimSuffix(path, "/"))
+			s.mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return s
+}
+
+func collectScrapes(t *testing.T, results <-chan ScrapeManyResult) []ScrapeManyResult {
+	t.Helper()
+	var got []ScrapeManyResult
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				return got
+			}
+			got = append(got, result)
+		case <-timeout:
+			t.Fatalf("results channel not closed, got %d results", len(got))
+		}
+	}
+}
+
+func TestClient_ScrapeMany(t *testing.T) {
+	server := newScrapePoolServer(t)
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	var urls []string
+	for i := 0; i < 10; i++ {
+		urls = append(urls, fmt.Sprintf("https://example.com/%d?sleep=10", i))
+	}
+
+	results := collectScrapes(t, client.ScrapeMany(context.Background(), urls, &ScrapeManyOptions{Workers: 3}))
+
+	if len(results) != len(urls) {
+		t.Fatalf("ScrapeMany() returned %d results, want %d", len(results), len(urls))
+	}
+	seen := map[int]bool{}
+	for _, result := range results {
+		if result.Err != nil {
+			t.Errorf("ScrapeMany() result %d error = %v", result.Index, result.Err)
+			continue
+		}
+		if result.URL != urls[result.Index] || result.Result.Result.URL != result.URL {
+			t.Errorf("ScrapeMany() result %d = %s (%s), want %s", result.Index, result.URL, result.Result.Result.URL, urls[result.Index])
+		}
+		seen[result.Index] = true
+	}
+	if len(seen) != len(urls) {
+		t.Errorf("ScrapeMany() returned %d distinct URLs, want %d", len(seen), len(urls))
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.maxActive > 3 {
+		t.Errorf("crawl requests in flight = %d, want at most 3", server.maxActive)
+	}
+}
+
+func TestClient_ScrapeMany_Ordered(t *testing.T) {
+	server := newScrapePoolServer(t)
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	urls := []string{
+		"https://example.com/0?sleep=100",
+		"https://example.com/1?sleep=50",
+		"https://example.com/2",
+		"https://example.com/3",
+	}
+
+	results := collectScrapes(t, client.ScrapeMany(context.Background(), urls, &ScrapeManyOptions{
+		Workers: 4,
+		Ordered: true,
+	}))
+
+	if len(results) != len(urls) {
+		t.Fatalf("ScrapeMany() returned %d results, want %d", len(results), len(urls))
+	}
+	for i, result := range results {
+		if result.Index != i {
+			t.Errorf("ScrapeMany() result %d has index %d", i, result.Index)
+		}
+	}
+}
+
+func TestClient_ScrapeMany_JobTimeout(t *testing.T) {
+	server := newScrapePoolServer(t)
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	urls := []string{"https://example.com/ok", "https://example.com/hang"}
+	results := collectScrapes(t, client.ScrapeMany(context.Background(), urls, &ScrapeManyOptions{
+		JobTimeout: 100 * time.Millisecond,
+		Ordered:    true,
+	}))
+
+	if len(results) != 2 {
+		t.Fatalf("ScrapeMany() returned %d results, want 2", len(results))
+	}
+	if results[0].Err != nil {
+		t.Errorf("ScrapeMany() result 0 error = %v", results[0].Err)
+	}
+	if !errors.Is(results[1].Err, context.DeadlineExceeded) {
+		t.Errorf("ScrapeMany() result 1 error = %v, want %v", results[1].Err, context.DeadlineExceeded)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.stopped) != 1 || server.urls[server.stopped[0]] != "https://example.com/hang" {
+		t.Errorf("stopped crawl requests = %v, want the one of https://example.com/hang", server.stopped)
+	}
+}
+
+func TestClient_ScrapeMany_Canceled(t *testing.T) {
+	server := newScrapePoolServer(t)
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	urls := []string{"https://example.com/hang", "https://example.com/hang", "https://example.com/next"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	results := collectScrapes(t, client.ScrapeMany(ctx, urls, &ScrapeManyOptions{Workers: 2}))
+
+	if len(results) != len(urls) {
+		t.Fatalf("ScrapeMany() returned %d results, want %d", len(results), len(urls))
+	}
+	for _, result := range results {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("ScrapeMany() result %d error = %v, want %v", result.Index, result.Err, context.Canceled)
+		}
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.stopped) != 2 {
+		t.Errorf("stopped crawl requests = %v, want 2", server.stopped)
+	}
+}
+
+func TestClient_ScrapeMany_Empty(t *testing.T) {
+	client := NewClient("test-key", "")
+
+	if results := collectScrapes(t, client.ScrapeMany(context.Background(), nil, nil)); len(results) != 0 {
+		t.Errorf("ScrapeMany() returned %d results, want 0", len(results))
+	}
+}
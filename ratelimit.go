@@ -0,0 +1,168 @@
+package watercrawl
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitInfo is the rate limit and quota state reported by the API response headers.
+// Counts the server did not report are -1.
+type RateLimitInfo struct {
+	// Limit is the number of requests allowed in the current window
+	Limit int
+	// Remaining is the number of requests left in the current window
+	Remaining int
+	// Reset is when the current window resets, or the zero time if unknown
+	Reset time.Time
+	// RetryAfter is the delay requested by the Retry-After header, or 0
+	RetryAfter time.Duration
+	// QuotaLimit is the plan quota, e.g. pages per billing period
+	QuotaLimit int
+	// QuotaRemaining is what is left of the plan quota
+	QuotaRemaining int
+}
+
+// RateLimitError is returned for 429 Too Many Requests responses.
+// It unwraps to the underlying *APIError, so errors.Is(err, ErrRateLimited) holds.
+type RateLimitError struct {
+	*APIError
+	RateLimit  RateLimitInfo
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("watercrawl: rate limited, retry after %s: %s", e.RetryAfter, e.APIError.Message)
+	}
+	return fmt.Sprintf("watercrawl: rate limited: %s", e.APIError.Message)
+}
+
+// Unwrap returns the underlying *APIError
+func (e *RateLimitError) Unwrap() error {
+	return e.APIError
+}
+
+// WithRateLimitCallback registers fn to be called after any response reporting
+// a remaining request or quota budget at or below threshold. fn is called
+// synchronously from the requesting goroutine and must not block.
+func WithRateLimitCallback(threshold int, fn func(RateLimitInfo)) Option {
+	return func(c *Client) {
+		c.rateLimitThreshold = threshold
+		c.rateLimitCallback = fn
+	}
+}
+
+// RateLimit returns the rate limit information of the most recent response
+// that carried rate limit headers. ok is false if none has been seen yet.
+func (c *Client) RateLimit() (info RateLimitInfo, ok bool) {
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+
+	if c.rateLimit == nil {
+		return RateLimitInfo{}, false
+	}
+	return *c.rateLimit, true
+}
+
+// recordRateLimit stores the rate limit headers of resp and runs the callback
+func (c *Client) recordRateLimit(resp *http.Response) {
+	info, ok := parseRateLimitInfo(resp.Header, time.Now())
+	if !ok {
+		return
+	}
+
+	c.rateLimitMu.Lock()
+	c.rateLimit = &info
+	c.rateLimitMu.Unlock()
+
+	if c.rateLimitCallback == nil {
+		return
+	}
+	if (info.Remaining >= 0 && info.Remaining <= c.rateLimitThreshold) ||
+		(info.QuotaRemaining >= 0 && info.QuotaRemaining <= c.rateLimitThreshold) {
+		c.rateLimitCallback(info)
+	}
+}
+
+// errorFromResponse builds the error for an error response
+func (c *Client) errorFromResponse(resp *http.Response, body []byte) error {
+	apiErr := newAPIError(resp, body)
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return apiErr
+	}
+
+	info, _ := parseRateLimitInfo(resp.Header, time.Now())
+	retryAfter := info.RetryAfter
+	if retryAfter == 0 && !info.Reset.IsZero() {
+		retryAfter = time.Until(info.Reset)
+		if retryAfter < 0 {
+			retryAfter = 0
+		}
+	}
+
+	return &RateLimitError{
+		APIError:   apiErr,
+		RateLimit:  info,
+		RetryAfter: retryAfter,
+	}
+}
+
+// parseRateLimitInfo reads the X-RateLimit-*, RateLimit-*, X-Quota-* and
+// Retry-After headers. ok is false when none of them is present.
+func parseRateLimitInfo(header http.Header, now time.Time) (info RateLimitInfo, ok bool) {
+	info = RateLimitInfo{Limit: -1, Remaining: -1, QuotaLimit: -1, QuotaRemaining: -1}
+
+	if v, found := headerInt(header, "X-RateLimit-Limit", "RateLimit-Limit"); found {
+		info.Limit = v
+		ok = true
+	}
+	if v, found := headerInt(header, "X-RateLimit-Remaining", "RateLimit-Remaining"); found {
+		info.Remaining = v
+		ok = true
+	}
+	if v, found := headerInt(header, "X-RateLimit-Reset", "RateLimit-Reset"); found {
+		info.Reset = resetTime(v, now)
+		ok = true
+	}
+	if v, found := headerInt(header, "X-Quota-Limit"); found {
+		info.QuotaLimit = v
+		ok = true
+	}
+	if v, found := headerInt(header, "X-Quota-Remaining"); found {
+		info.QuotaRemaining = v
+		ok = true
+	}
+	if d, found := parseRetryAfter(header.Get("Retry-After"), now); found {
+		info.RetryAfter = d
+		ok = true
+	}
+
+	return info, ok
+}
+
+// headerInt returns the first of the named headers holding an integer
+func headerInt(header http.Header, names ...string) (int, bool) {
+	for _, name := range names {
+		value := strings.TrimSpace(header.Get(name))
+		if value == "" {
+			continue
+		}
+		if v, err := strconv.Atoi(value); err == nil {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// resetTime interprets a reset header as a Unix timestamp when it is large
+// enough to be one, and as a number of seconds from now otherwise
+func resetTime(value int, now time.Time) time.Time {
+	const unixThreshold = 1000000000
+	if value >= unixThreshold {
+		return time.Unix(int64(value), 0)
+	}
+	return now.Add(time.Duration(value) * time.Second)
+}
@@ -0,0 +1,130 @@
+package watercrawl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimitInfo(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   RateLimitInfo
+		wantOK bool
+	}{
+		{
+			name:   "no headers",
+			header: http.Header{},
+			want:   RateLimitInfo{Limit: -1, Remaining: -1, QuotaLimit: -1, QuotaRemaining: -1},
+			wantOK: false,
+		},
+		{
+			name: "X-RateLimit headers with Unix reset",
+			header: http.Header{
+				"X-Ratelimit-Limit":     []string{"100"},
+				"X-Ratelimit-Remaining": []string{"0"},
+				"X-Ratelimit-Reset":     []string{"1740830460"},
+			},
+			want:   RateLimitInfo{Limit: 100, Remaining: 0, Reset: time.Unix(1740830460, 0), QuotaLimit: -1, QuotaRemaining: -1},
+			wantOK: true,
+		},
+		{
+			name: "RateLimit headers with relative reset, quota and Retry-After",
+			header: http.Header{
+				"Ratelimit-Limit":     []string{"60"},
+				"Ratelimit-Remaining": []string{"5"},
+				"Ratelimit-Reset":     []string{"30"},
+				"X-Quota-Limit":       []string{"1000"},
+				"X-Quota-Remaining":   []string{"250"},
+				"Retry-After":         []string{"12"},
+			},
+			want: RateLimitInfo{
+				Limit:          60,
+				Remaining:      5,
+				Reset:          now.Add(30 * time.Second),
+				RetryAfter:     12 * time.Second,
+				QuotaLimit:     1000,
+				QuotaRemaining: 250,
+			},
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRateLimitInfo(tt.header, now)
+			if ok != tt.wantOK {
+				t.Errorf("parseRateLimitInfo() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got.Limit != tt.want.Limit || got.Remaining != tt.want.Remaining ||
+				!got.Reset.Equal(tt.want.Reset) || got.RetryAfter != tt.want.RetryAfter ||
+				got.QuotaLimit != tt.want.QuotaLimit || got.QuotaRemaining != tt.want.QuotaRemaining {
+				t.Errorf("parseRateLimitInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_RateLimit(t *testing.T) {
+	remaining := "10"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "10")
+		w.Header().Set("X-RateLimit-Remaining", remaining)
+		if remaining == "0" {
+			w.Header().Set("Retry-After", "3")
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"detail":"Request was throttled."}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	var callbacks []RateLimitInfo
+	client := NewClient("test-key", server.URL+"/", WithRateLimitCallback(2, func(info RateLimitInfo) {
+		callbacks = append(callbacks, info)
+	}))
+
+	if _, ok := client.RateLimit(); ok {
+		t.Error("RateLimit() ok = true before any request")
+	}
+
+	if err := client.StopCrawlRequest(context.Background(), "test-uuid"); err != nil {
+		t.Fatalf("StopCrawlRequest() error = %v", err)
+	}
+	info, ok := client.RateLimit()
+	if !ok || info.Limit != 10 || info.Remaining != 10 {
+		t.Errorf("RateLimit() = %+v, %v, want limit 10 and remaining 10", info, ok)
+	}
+	if len(callbacks) != 0 {
+		t.Errorf("callback called %d times above threshold", len(callbacks))
+	}
+
+	remaining = "0"
+	err := client.StopCrawlRequest(context.Background(), "test-uuid")
+
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("StopCrawlRequest() error = %v, want RateLimitError", err)
+	}
+	if rateLimitErr.RetryAfter != 3*time.Second {
+		t.Errorf("RetryAfter = %v, want %v", rateLimitErr.RetryAfter, 3*time.Second)
+	}
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("errors.Is(%v, ErrRateLimited) = false", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Detail != "Request was throttled." {
+		t.Errorf("errors.As(APIError) = %v, want detail", apiErr)
+	}
+	if len(callbacks) != 1 || callbacks[0].Remaining != 0 {
+		t.Errorf("callbacks = %+v, want one call with remaining 0", callbacks)
+	}
+}
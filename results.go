@@ -0,0 +1,214 @@
+package watercrawl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrResultNotInline is returned by CrawlResult.ResultData when the API returned
+// the result as a URL; use Client.FetchResultData to download it.
+var ErrResultNotInline = errors.New("watercrawl: result is not inline, fetch it from its URL")
+
+// ResultData is the content extracted from a crawled page
+type ResultData struct {
+	Markdown string         `json:"markdown,omitempty"`
+	HTML     string         `json:"html,omitempty"`
+	Links    []string       `json:"links,omitempty"`
+	Metadata ResultMetadata `json:"metadata"`
+
+	// Extra holds fields not covered above, such as plugin output
+	Extra map[string]interface{} `json:"-"`
+}
+
+// ResultMetadata is the metadata extracted from a crawled page
+type ResultMetadata struct {
+	Title         string `json:"title,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Keywords      string `json:"keywords,omitempty"`
+	Language      string `json:"language,omitempty"`
+	Favicon       string `json:"favicon,omitempty"`
+	OGTitle       string `json:"og:title,omitempty"`
+	OGDescription string `json:"og:description,omitempty"`
+	OGImage       string `json:"og:image,omitempty"`
+
+	// Extra holds metadata fields not covered above
+	Extra map[string]interface{} `json:"-"`
+}
+
+// Attachment is a file produced while crawling a page, such as a screenshot
+type Attachment struct {
+	UUID           string `json:"uuid"`
+	Attachment     string `json:"attachment"` // URL of the file
+	AttachmentType string `json:"attachment_type"`
+	Filename       string `json:"filename"`
+}
+
+// Attachment types produced by page actions
+const (
+	AttachmentTypeScreenshot = "screenshot"
+	AttachmentTypePDF        = "pdf"
+)
+
+// MarshalJSON implements json.Marshaler, merging Extra into the output
+func (d ResultData) MarshalJSON() ([]byte, error) {
+	type resultData ResultData
+	return marshalWithExtra(resultData(d), d.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, collecting unknown keys into Extra
+func (d *ResultData) UnmarshalJSON(data []byte) error {
+	type resultData ResultData
+	var v resultData
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*d = ResultData(v)
+	d.Extra = extra
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, merging Extra into the output
+func (m ResultMetadata) MarshalJSON() ([]byte, error) {
+	type resultMetadata ResultMetadata
+	return marshalWithExtra(resultMetadata(m), m.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, collecting unknown keys into Extra
+func (m *ResultMetadata) UnmarshalJSON(data []byte) error {
+	type resultMetadata ResultMetadata
+	var v resultMetadata
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*m = ResultMetadata(v)
+	m.Extra = extra
+	return nil
+}
+
+// ResultURL returns the URL of the result content when the API returned it by reference
+func (r *CrawlResult) ResultURL() (string, bool) {
+	var u string
+	if err := json.Unmarshal(r.Result, &u); err != nil || u == "" {
+		return "", false
+	}
+	return u, true
+}
+
+// ResultData decodes the inline result content.
+// It returns ErrResultNotInline when the content must be fetched from ResultURL.
+func (r *CrawlResult) ResultData() (*ResultData, error) {
+	if _, ok := r.ResultURL(); ok {
+		return nil, ErrResultNotInline
+	}
+
+	var data ResultData
+	switch {
+	case len(r.Result) > 0 && string(r.Result) != "null":
+		if err := json.Unmarshal(r.Result, &data); err != nil {
+			return nil, fmt.Errorf("failed to decode result data: %w", err)
+		}
+	case r.Data != nil:
+		raw, err := json.Marshal(r.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode result data: %w", err)
+		}
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return nil, fmt.Errorf("failed to decode result data: %w", err)
+		}
+	}
+
+	return &data, nil
+}
+
+// AttachmentsOfType returns the attachments of the given type, e.g. AttachmentTypeScreenshot
+func (r *CrawlResult) AttachmentsOfType(attachmentType string) []Attachment {
+	var attachments []Attachment
+	for _, attachment := range r.Attachments {
+		if attachment.AttachmentType == attachmentType {
+			attachments = append(attachments, attachment)
+		}
+	}
+	return attachments
+}
+
+// FetchResultData returns the content of a crawl result, downloading it when
+// the API returned it as a URL rather than inline. The API key is not sent
+// with the download since the URL points at result storage.
+func (c *Client) FetchResultData(ctx context.Context, result *CrawlResult) (*ResultData, error) {
+	data, err := result.ResultData()
+	if !errors.Is(err, ErrResultNotInline) {
+		return data, err
+	}
+
+	resultURL, _ := result.ResultURL()
+	c.logger.Debugf("Fetching result data for %s", result.URL)
+
+	data = &ResultData{}
+	if err := c.fetchStored(ctx, resultURL, data); err != nil {
+		return nil, fmt.Errorf("failed to fetch result data: %w", err)
+	}
+
+	return data, nil
+}
+
+// fetchStored downloads and decodes a JSON document from result storage.
+// The API key is not sent since the URL does not point at the API.
+func (c *Client) fetchStored(ctx context.Context, storedURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, storedURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.send(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer c.closeBody(resp)
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return newAPIError(resp, body)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// DownloadCrawlResults downloads all results of a crawl request as typed values
+func (c *Client) DownloadCrawlResults(ctx context.Context, id string) ([]CrawlResult, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, c.apiPath("core/crawl-requests/%s/download/", id), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	if err := c.processResponse(resp, &raw); err != nil {
+		return nil, err
+	}
+
+	var results []CrawlResult
+	if err := json.Unmarshal(raw, &results); err == nil {
+		return results, nil
+	}
+
+	// Some responses wrap the results in an object
+	var wrapped struct {
+		Results []CrawlResult `json:"results"`
+	}
+	if err := json.Unmarshal(raw, &wrapped); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return wrapped.Results, nil
+}
@@ -0,0 +1,133 @@
+package watercrawl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCrawlResult_ResultData(t *testing.T) {
+	input := `{
+		"uuid": "result-uuid",
+		"url": "https://example.com",
+		"result": {
+			"markdown": "# Example",
+			"links": ["https://example.com/about"],
+			"metadata": {"title": "Example", "og:image": "https://example.com/og.png", "author": "someone"},
+			"openai_extract": {"summary": "text"}
+		},
+		"attachments": [
+			{"uuid": "a1", "attachment": "https://storage.example.com/a1.png", "attachment_type": "screenshot", "filename": "a1.png"},
+			{"uuid": "a2", "attachment": "https://storage.example.com/a2.pdf", "attachment_type": "pdf", "filename": "a2.pdf"}
+		]
+	}`
+
+	var result CrawlResult
+	if err := json.Unmarshal([]byte(input), &result); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if _, ok := result.ResultURL(); ok {
+		t.Error("ResultURL() ok = true for inline result")
+	}
+
+	data, err := result.ResultData()
+	if err != nil {
+		t.Fatalf("ResultData() error = %v", err)
+	}
+	if data.Markdown != "# Example" {
+		t.Errorf("Markdown = %q, want %q", data.Markdown, "# Example")
+	}
+	if len(data.Links) != 1 {
+		t.Errorf("Links = %v, want 1 link", data.Links)
+	}
+	if data.Metadata.Title != "Example" || data.Metadata.OGImage != "https://example.com/og.png" {
+		t.Errorf("Metadata = %+v, want title and og:image", data.Metadata)
+	}
+	if data.Metadata.Extra["author"] != "someone" {
+		t.Errorf("Metadata.Extra = %v, want author", data.Metadata.Extra)
+	}
+	if data.Extra["openai_extract"] == nil {
+		t.Errorf("Extra = %v, want openai_extract", data.Extra)
+	}
+
+	screenshots := result.AttachmentsOfType(AttachmentTypeScreenshot)
+	if len(screenshots) != 1 || screenshots[0].UUID != "a1" {
+		t.Errorf("AttachmentsOfType(screenshot) = %+v, want a1", screenshots)
+	}
+}
+
+func TestCrawlResult_ResultData_LegacyData(t *testing.T) {
+	result := CrawlResult{
+		Data: map[string]interface{}{"markdown": "legacy"},
+	}
+
+	data, err := result.ResultData()
+	if err != nil {
+		t.Fatalf("ResultData() error = %v", err)
+	}
+	if data.Markdown != "legacy" {
+		t.Errorf("Markdown = %q, want %q", data.Markdown, "legacy")
+	}
+}
+
+func TestClient_FetchResultData(t *testing.T) {
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if apiKey := r.Header.Get("X-API-Key"); apiKey != "" {
+			t.Errorf("API key sent to result storage: %q", apiKey)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write([]byte(`{"markdown":"fetched","metadata":{"title":"Remote"}}`)); err != nil {
+			t.Errorf("Failed to write response: %v", err)
+		}
+	}))
+	defer storage.Close()
+
+	result := CrawlResult{
+		URL:    "https://example.com",
+		Result: json.RawMessage(`"` + storage.URL + `/result.json"`),
+	}
+
+	if _, err := result.ResultData(); !errors.Is(err, ErrResultNotInline) {
+		t.Errorf("ResultData() error = %v, want %v", err, ErrResultNotInline)
+	}
+
+	client := NewClient("test-key", "")
+	data, err := client.FetchResultData(context.Background(), &result)
+	if err != nil {
+		t.Fatalf("FetchResultData() error = %v", err)
+	}
+	if data.Markdown != "fetched" || data.Metadata.Title != "Remote" {
+		t.Errorf("FetchResultData() = %+v, want fetched markdown and title", data)
+	}
+}
+
+func TestClient_DownloadCrawlResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/core/crawl-requests/test-uuid/download/" {
+			t.Errorf("Expected path /api/v1/core/crawl-requests/test-uuid/download/, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write([]byte(`[{"uuid":"r1","url":"https://example.com","result":{"markdown":"one"}},{"uuid":"r2","url":"https://example.com/2"}]`)); err != nil {
+			t.Errorf("Failed to write response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	results, err := client.DownloadCrawlResults(context.Background(), "test-uuid")
+	if err != nil {
+		t.Fatalf("DownloadCrawlResults() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("DownloadCrawlResults() length = %d, want 2", len(results))
+	}
+	data, err := results[0].ResultData()
+	if err != nil || data.Markdown != "one" {
+		t.Errorf("results[0].ResultData() = %+v, %v, want markdown one", data, err)
+	}
+}
@@ -0,0 +1,164 @@
+package watercrawl
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"math"
+	mathrand "math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IdempotencyKeyHeader is the header used to mark a POST request as safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// RetryPolicy controls how failed requests are retried.
+// Only idempotent requests (GET, HEAD, OPTIONS, PUT, DELETE) and requests
+// carrying an idempotency key are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// A value of 0 or 1 disables retries.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts. A response asking with
+	// Retry-After for a longer delay is not retried but returned as an
+	// error, such as a *RateLimitError carrying the RetryAfter delay.
+	MaxBackoff time.Duration
+
+	// Multiplier is applied to the delay after every attempt
+	Multiplier float64
+
+	// Jitter randomizes each delay by up to this fraction (0 to 1) of its value
+	Jitter float64
+
+	// RetryableStatusCodes lists the HTTP status codes that trigger a retry
+	RetryableStatusCodes []int
+
+	// RetryNetworkErrors retries requests that failed without a response
+	RetryNetworkErrors bool
+}
+
+// DefaultRetryPolicy returns a retry policy suitable for most workloads:
+// three attempts with exponential backoff on 408, 429 and 5xx gateway errors.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		RetryableStatusCodes: []int{
+			http.StatusRequestTimeout,
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryNetworkErrors: true,
+	}
+}
+
+// WithRetryPolicy sets the retry policy of the client. Retries are disabled by default.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// NewIdempotencyKey returns a random key suitable for CreateCrawlRequestInput.IdempotencyKey
+func NewIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand never fails on supported platforms; fall back to the clock
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
+
+// retryableStatus reports whether the status code should be retried
+func (p RetryPolicy) retryableStatus(statusCode int) bool {
+	for _, code := range p.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the delay to wait after the given failed attempt
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		delay += delay * jitter * (2*mathrand.Float64() - 1)
+	}
+
+	return time.Duration(delay)
+}
+
+// isIdempotent reports whether a request may be safely retried
+func isIdempotent(method string, header http.Header) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return header.Get(IdempotencyKeyHeader) != ""
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
+
+// sleepContext waits for the given duration or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,193 @@
+package watercrawl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+	policy.Jitter = 0
+	return policy
+}
+
+func TestClient_Retry_GET(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(CrawlRequest{UUID: "test-uuid"}); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/", WithRetryPolicy(testRetryPolicy()))
+
+	result, err := client.GetCrawlRequest(context.Background(), "test-uuid")
+	if err != nil {
+		t.Fatalf("GetCrawlRequest() error = %v", err)
+	}
+	if result.UUID != "test-uuid" {
+		t.Errorf("GetCrawlRequest().UUID = %v, want %v", result.UUID, "test-uuid")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestClient_Retry_Exhausted(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/", WithRetryPolicy(testRetryPolicy()))
+
+	_, err := client.GetCrawlRequest(context.Background(), "test-uuid")
+	if err == nil {
+		t.Fatal("Expected error after exhausting retries, got nil")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestClient_Retry_RetryAfterBeyondMaxBackoff(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/", WithRetryPolicy(testRetryPolicy()))
+
+	start := time.Now()
+	_, err := client.GetCrawlRequest(context.Background(), "test-uuid")
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) || rateLimitErr.RetryAfter != time.Hour {
+		t.Fatalf("GetCrawlRequest() error = %v, want *RateLimitError with RetryAfter %s", err, time.Hour)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GetCrawlRequest() took %s, want no wait", elapsed)
+	}
+}
+
+func TestClient_Retry_POST(t *testing.T) {
+	tests := []struct {
+		name           string
+		idempotencyKey string
+		wantAttempts   int32
+		wantErr        bool
+	}{
+		{
+			name:         "without idempotency key",
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:           "with idempotency key",
+			idempotencyKey: "key-123",
+			wantAttempts:   2,
+			wantErr:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if key := r.Header.Get(IdempotencyKeyHeader); key != tt.idempotencyKey {
+					t.Errorf("Expected %s header %q, got %q", IdempotencyKeyHeader, tt.idempotencyKey, key)
+				}
+				var input map[string]interface{}
+				if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+					t.Errorf("Failed to decode request body: %v", err)
+				}
+				if atomic.AddInt32(&attempts, 1) == 1 {
+					w.WriteHeader(http.StatusBadGateway)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if err := json.NewEncoder(w).Encode(CrawlRequest{UUID: "test-uuid"}); err != nil {
+					t.Errorf("Failed to encode response: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient("test-key", server.URL+"/", WithRetryPolicy(testRetryPolicy()))
+
+			_, err := client.CreateCrawlRequest(context.Background(), CreateCrawlRequestInput{
+				URL:            URLs{"https://example.com"},
+				IdempotencyKey: tt.idempotencyKey,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateCrawlRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "empty", value: "", wantOK: false},
+		{name: "seconds", value: "7", want: 7 * time.Second, wantOK: true},
+		{name: "negative seconds", value: "-1", wantOK: false},
+		{name: "http date", value: "Sat, 01 Mar 2025 12:00:30 GMT", want: 30 * time.Second, wantOK: true},
+		{name: "past http date", value: "Sat, 01 Mar 2025 11:00:00 GMT", want: 0, wantOK: true},
+		{name: "garbage", value: "soon", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value, now)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     300 * time.Millisecond,
+		Multiplier:     2,
+	}
+
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}
+	for i, w := range want {
+		if got := policy.backoff(i + 1); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
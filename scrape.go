@@ -0,0 +1,287 @@
+package watercrawl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ScrapeOptions configures Scrape
+type ScrapeOptions struct {
+	// PageOptions may be nil to use the server defaults
+	PageOptions   *PageOptions
+	PluginOptions PluginOptions
+
+	// Async returns as soon as the crawl request is created instead of
+	// waiting for its result
+	Async bool
+
+	// Download fetches the results with DownloadCrawlRequest once available
+	Download bool
+}
+
+// ScrapeSource tells where the result of a scrape came from
+type ScrapeSource string
+
+// Sources of a ScrapeResult
+const (
+	// ScrapeSourceAsync means the scrape did not wait for a result
+	ScrapeSourceAsync ScrapeSource = "async"
+	// ScrapeSourceResultEvent means the result came from a result event
+	ScrapeSourceResultEvent ScrapeSource = "result_event"
+	// ScrapeSourceDownload means the result was downloaded
+	ScrapeSourceDownload ScrapeSource = "download"
+	// ScrapeSourceState means no result was received and Data holds the last
+	// state of the crawl request
+	ScrapeSourceState ScrapeSource = "state"
+)
+
+// String returns the source as a string
+func (s ScrapeSource) String() string {
+	return string(s)
+}
+
+// ScrapeResult is the outcome of Scrape
+type ScrapeResult struct {
+	// Request is the last known state of the crawl request
+	Request *CrawlRequest
+
+	// Result is the typed result, nil if none was received
+	Result *CrawlResult
+
+	// Source tells where Result and Data came from
+	Source ScrapeSource
+
+	// Data is the result as received: the result event data, the download,
+	// the last state, or the UUID and status for an async scrape
+	Data map[string]interface{}
+}
+
+// Scrape performs a single URL scrape. opts may be nil. If ctx ends while
+// waiting for the result, the crawl request is abandoned as described in
+// RunOwnedCrawl.
+func (c *Client) Scrape(ctx context.Context, url string, opts *ScrapeOptions) (*ScrapeResult, error) {
+	if opts == nil {
+		opts = &ScrapeOptions{}
+	}
+
+	request, err := c.createScrape(ctx, url, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Async {
+		return asyncScrapeResult(request), nil
+	}
+
+	return c.awaitOwnedScrape(ctx, request, opts.Download)
+}
+
+// awaitOwnedScrape awaits a scrape, abandoning its crawl request if ctx ends first
+func (c *Client) awaitOwnedScrape(ctx context.Context, request *CrawlRequest, download bool) (*ScrapeResult, error) {
+	var result *ScrapeResult
+	err := c.ownCrawl(ctx, request.UUID, func() (err error) {
+		result, err = c.awaitScrape(ctx, request, download)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// createScrape creates the crawl request scraping url
+func (c *Client) createScrape(ctx context.Context, url string, opts *ScrapeOptions) (*CrawlRequest, error) {
+	input := CreateCrawlRequestInput{
+		URL: URLs{url},
+		Options: CrawlOptions{
+			SpiderOptions: SpiderOptions{
+				AllowedDomains: []string{"*"},
+			},
+			PluginOptions: opts.PluginOptions,
+		},
+	}
+	if opts.PageOptions != nil {
+		input.Options.PageOptions = *opts.PageOptions
+	}
+
+	request, err := c.CreateCrawlRequest(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Infof("Crawl request created with UUID: %s, Status: %s", request.UUID, request.Status)
+	return request, nil
+}
+
+// asyncScrapeResult returns the result of a scrape that does not wait for the crawl
+func asyncScrapeResult(request *CrawlRequest) *ScrapeResult {
+	return &ScrapeResult{
+		Request: request,
+		Source:  ScrapeSourceAsync,
+		// Plain strings keep the map returned by ScrapeURL in its original shape
+		Data: map[string]interface{}{
+			"uuid":   request.UUID,
+			"status": string(request.Status),
+		},
+	}
+}
+
+// awaitScrape monitors a scrape until its result is available
+func (c *Client) awaitScrape(ctx context.Context, request *CrawlRequest, download bool) (*ScrapeResult, error) {
+	c.logger.Debugf("Monitoring crawl request %s", request.UUID)
+	monitor, err := c.WatchCrawlRequest(ctx, request.UUID, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Stop monitoring once a result has been picked
+	defer monitor.Close()
+
+	eventCount := 0
+	var lastProgress float64
+	var lastError interface{}
+	var lastStateData map[string]interface{}
+
+	for event := range monitor.Events() {
+		eventCount++
+		c.logger.Debugf("Received event #%d of type: %s", eventCount, event.Type)
+
+		switch event.Type {
+		case EventTypeResult:
+			c.logger.Debugf("Found result event")
+			data, ok := event.Data.(map[string]interface{})
+			if !ok {
+				c.logger.Warnf("Result event has unexpected data type: %T", event.Data)
+				continue
+			}
+			if download {
+				if result := c.downloadScrape(request); result != nil {
+					return result, nil
+				}
+			}
+			return &ScrapeResult{
+				Request: request,
+				Result:  crawlResultFromData(data),
+				Source:  ScrapeSourceResultEvent,
+				Data:    data,
+			}, nil
+		case EventTypeError:
+			c.logger.Warnf("Error event received: %v", event.Data)
+			lastError = event.Data
+		case EventTypeProgress:
+			if typed, err := event.Event(); err == nil {
+				lastProgress = typed.(ProgressEvent).Progress
+				c.logger.Infof("Progress: %.2f%%", lastProgress)
+			}
+		case EventTypeState:
+			// Save state data in case we don't get a result event
+			stateData, ok := event.Data.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			lastStateData = stateData
+
+			typed, err := event.Event()
+			if err != nil {
+				continue
+			}
+			state := typed.(StateEvent).Request
+			request = &state
+
+			// Check if the crawl reached a terminal status
+			if request.Status.IsSuccess() {
+				c.logger.Infof("Crawl completed according to state event")
+				if download {
+					if result := c.downloadScrape(request); result != nil {
+						return result, nil
+					}
+				}
+				// If download failed or wasn't requested, return the state data
+				return &ScrapeResult{Request: request, Source: ScrapeSourceState, Data: stateData}, nil
+			} else if request.Status.IsTerminal() {
+				return nil, fmt.Errorf("crawl failed with status: %s", request.Status)
+			}
+		case EventTypeCompleted:
+			c.logger.Infof("Crawl completed event received")
+			// If we receive a completed event but haven't received a result yet, try to download
+			if download {
+				c.logger.Debugf("Attempting to download final results")
+				if result := c.downloadScrape(request); result != nil {
+					return result, nil
+				}
+			}
+		}
+	}
+
+	// The stream ended early, e.g. because ctx is done
+	if err := monitor.Err(); err != nil {
+		return nil, fmt.Errorf("monitoring crawl request %s: %w", request.UUID, err)
+	}
+
+	// If we have state data but no result, return the state data
+	if lastStateData != nil {
+		return &ScrapeResult{Request: request, Source: ScrapeSourceState, Data: lastStateData}, nil
+	}
+
+	// If we get here, we didn't receive a valid result
+	if eventCount == 0 {
+		return nil, fmt.Errorf("no events received from crawl request (timeout or connection error)")
+	} else if lastError != nil {
+		return nil, fmt.Errorf("crawl request failed with error: %v", lastError)
+	} else {
+		return nil, fmt.Errorf("received %d events (last progress: %.2f%%) but no valid result event",
+			eventCount, lastProgress)
+	}
+}
+
+// downloadScrape downloads the results of a scrape, returning nil if the
+// download failed or was empty
+func (c *Client) downloadScrape(request *CrawlRequest) *ScrapeResult {
+	// Use a detached context so the download is not cut short with the monitor
+	downloadCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	data, err := c.DownloadCrawlRequest(downloadCtx, request.UUID)
+	cancel()
+
+	switch {
+	case err != nil:
+		c.logger.Warnf("Error downloading result data: %v", err)
+		return nil
+	case len(data) == 0:
+		c.logger.Warnf("Downloaded results were empty")
+		return nil
+	}
+
+	c.logger.Debugf("Successfully downloaded result data")
+	return &ScrapeResult{
+		Request: request,
+		Result:  crawlResultFromData(data),
+		Source:  ScrapeSourceDownload,
+		Data:    data,
+	}
+}
+
+// crawlResultFromData decodes the first crawl result of result event or
+// download data, returning nil if it holds none
+func crawlResultFromData(data map[string]interface{}) *CrawlResult {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+
+	// Downloads wrap the results in a list
+	if _, ok := data["results"]; ok {
+		result, err := NewResultDecoder(bytes.NewReader(raw)).Next()
+		if err != nil {
+			return nil
+		}
+		return result
+	}
+
+	var result CrawlResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil
+	}
+	return &result
+}
@@ -0,0 +1,166 @@
+package watercrawl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newScrapeServer serves a crawl request whose status stream sends events,
+// and whose download returns download
+func newScrapeServer(t *testing.T, events []EventStreamMessage, download interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/core/crawl-requests/":
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(CrawlRequest{UUID: "test-uuid", Status: CrawlStatusNew}); err != nil {
+				t.Errorf("Failed to encode response: %v", err)
+			}
+		case "/api/v1/core/crawl-requests/test-uuid/status/":
+			w.Header().Set("Content-Type", "text/event-stream")
+			for _, event := range events {
+				data, err := json.Marshal(event)
+				if err != nil {
+					t.Errorf("Failed to marshal event: %v", err)
+				}
+				fmt.Fprintf(w, "data: %s\n\n", data)
+			}
+		case "/api/v1/core/crawl-requests/test-uuid/download/":
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(download); err != nil {
+				t.Errorf("Failed to encode response: %v", err)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestClient_Scrape(t *testing.T) {
+	resultEvent := EventStreamMessage{
+		Type: EventTypeResult,
+		Data: map[string]interface{}{"uuid": "result-uuid", "url": "https://example.com", "title": "Example"},
+	}
+	finishedEvent := EventStreamMessage{
+		Type: EventTypeState,
+		Data: map[string]interface{}{"uuid": "test-uuid", "status": "finished"},
+	}
+	download := []map[string]interface{}{{"uuid": "downloaded-uuid", "url": "https://example.com"}}
+
+	tests := []struct {
+		name       string
+		events     []EventStreamMessage
+		opts       *ScrapeOptions
+		wantSource ScrapeSource
+		wantStatus CrawlStatus
+		wantResult string
+	}{
+		{
+			name:       "async",
+			opts:       &ScrapeOptions{Async: true},
+			wantSource: ScrapeSourceAsync,
+			wantStatus: CrawlStatusNew,
+		},
+		{
+			name:       "result event",
+			events:     []EventStreamMessage{resultEvent, finishedEvent},
+			wantSource: ScrapeSourceResultEvent,
+			wantStatus: CrawlStatusNew,
+			wantResult: "result-uuid",
+		},
+		{
+			name:       "download on result event",
+			events:     []EventStreamMessage{resultEvent, finishedEvent},
+			opts:       &ScrapeOptions{Download: true},
+			wantSource: ScrapeSourceDownload,
+			wantStatus: CrawlStatusNew,
+			wantResult: "downloaded-uuid",
+		},
+		{
+			name:       "download on finished state",
+			events:     []EventStreamMessage{finishedEvent},
+			opts:       &ScrapeOptions{Download: true},
+			wantSource: ScrapeSourceDownload,
+			wantStatus: CrawlStatusFinished,
+			wantResult: "downloaded-uuid",
+		},
+		{
+			name:       "state fallback",
+			events:     []EventStreamMessage{finishedEvent},
+			wantSource: ScrapeSourceState,
+			wantStatus: CrawlStatusFinished,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newScrapeServer(t, tt.events, download)
+			defer server.Close()
+
+			client := NewClient("test-key", server.URL+"/")
+
+			got, err := client.Scrape(context.Background(), "https://example.com", tt.opts)
+			if err != nil {
+				t.Fatalf("Scrape() error = %v", err)
+			}
+			if got.Source != tt.wantSource {
+				t.Errorf("Scrape().Source = %v, want %v", got.Source, tt.wantSource)
+			}
+			if got.Request == nil || got.Request.UUID != "test-uuid" || got.Request.Status != tt.wantStatus {
+				t.Errorf("Scrape().Request = %+v, want test-uuid with status %v", got.Request, tt.wantStatus)
+			}
+			if got.Data == nil {
+				t.Errorf("Scrape().Data = nil, want data")
+			}
+
+			switch {
+			case tt.wantResult == "" && got.Result != nil:
+				t.Errorf("Scrape().Result = %+v, want nil", got.Result)
+			case tt.wantResult != "" && (got.Result == nil || got.Result.UUID != tt.wantResult):
+				t.Errorf("Scrape().Result = %+v, want UUID %v", got.Result, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestClient_Scrape_Failed(t *testing.T) {
+	server := newScrapeServer(t, []EventStreamMessage{{
+		Type: EventTypeState,
+		Data: map[string]interface{}{"uuid": "test-uuid", "status": "failed"},
+	}}, nil)
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	if _, err := client.Scrape(context.Background(), "https://example.com", nil); err == nil {
+		t.Errorf("Scrape() error = nil, want error")
+	}
+}
+
+func TestCrawlResultFromData(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]interface{}
+		want string
+	}{
+		{"result", map[string]interface{}{"uuid": "r1"}, "r1"},
+		{"results list", map[string]interface{}{"results": []interface{}{map[string]interface{}{"uuid": "r2"}}}, "r2"},
+		{"empty results list", map[string]interface{}{"results": []interface{}{}}, ""},
+		{"undecodable", map[string]interface{}{"uuid": 1}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := crawlResultFromData(tt.data)
+			switch {
+			case tt.want == "" && got != nil:
+				t.Errorf("crawlResultFromData() = %+v, want nil", got)
+			case tt.want != "" && (got == nil || got.UUID != tt.want):
+				t.Errorf("crawlResultFromData() = %+v, want UUID %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package watercrawl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// SearchDepth controls how thoroughly a search request visits its results
+type SearchDepth string
+
+// Depths of a search request
+const (
+	SearchDepthBasic    SearchDepth = "basic"
+	SearchDepthAdvanced SearchDepth = "advanced"
+	SearchDepthUltimate SearchDepth = "ultimate"
+)
+
+// SearchTimeRange restricts search results to recently published pages
+type SearchTimeRange string
+
+// Time ranges of a search request
+const (
+	SearchTimeRangeAny   SearchTimeRange = "any"
+	SearchTimeRangeHour  SearchTimeRange = "hour"
+	SearchTimeRangeDay   SearchTimeRange = "day"
+	SearchTimeRangeWeek  SearchTimeRange = "week"
+	SearchTimeRangeMonth SearchTimeRange = "month"
+	SearchTimeRangeYear  SearchTimeRange = "year"
+)
+
+// SearchOptions configures a web search
+type SearchOptions struct {
+	// Language is the language of the results, e.g. "en"
+	Language string `json:"language,omitempty"`
+	// Country is the country the search is run from, e.g. "us"
+	Country string `json:"country,omitempty"`
+	// TimeRange is sent as time_renge, the key spelled as the API expects it
+	TimeRange SearchTimeRange `json:"time_renge,omitempty"`
+	Depth     SearchDepth     `json:"depth,omitempty"`
+	// SearchType is the kind of search, "web" by default
+	SearchType string `json:"search_type,omitempty"`
+
+	// Extra holds options not covered above.
+	// Typed fields take precedence over Extra keys of the same name.
+	Extra map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements json.Marshaler, merging Extra into the output
+func (o SearchOptions) MarshalJSON() ([]byte, error) {
+	type searchOptions SearchOptions
+	return marshalWithExtra(searchOptions(o), o.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, collecting unknown keys into Extra
+func (o *SearchOptions) UnmarshalJSON(data []byte) error {
+	type searchOptions SearchOptions
+	var v searchOptions
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*o = SearchOptions(v)
+	o.Extra = extra
+	return nil
+}
+
+// SearchRequest represents a web search request
+type SearchRequest struct {
+	UUID        string        `json:"uuid"`
+	Query       string        `json:"query"`
+	Options     SearchOptions `json:"search_options"`
+	ResultLimit int           `json:"result_limit"`
+	Status      CrawlStatus   `json:"status"`
+	// Result holds the search results, inline or as the URL of a JSON
+	// document; use Client.GetSearchResults to retrieve them
+	Result    json.RawMessage `json:"result,omitempty"`
+	CreatedAt Timestamp       `json:"created_at"`
+	UpdatedAt Timestamp       `json:"updated_at"`
+}
+
+// SearchRequestList represents a paginated list of search requests
+type SearchRequestList struct {
+	Count    int             `json:"count"`
+	Next     *string         `json:"next"`
+	Previous *string         `json:"previous"`
+	Results  []SearchRequest `json:"results"`
+}
+
+// SearchResult is a page found by a search request
+type SearchResult struct {
+	URL         string `json:"url"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// Order is the rank of the result, starting at 1
+	Order int `json:"order"`
+}
+
+// CreateSearchRequestInput represents the input for creating a search request
+type CreateSearchRequestInput struct {
+	Query   string        `json:"query"`
+	Options SearchOptions `json:"search_options"`
+	// ResultLimit is the maximum number of results, the server default if zero
+	ResultLimit int `json:"result_limit,omitempty"`
+
+	// IdempotencyKey, if set, is sent as the Idempotency-Key header and makes
+	// the request eligible for retries
+	IdempotencyKey string `json:"-"`
+}
+
+// SearchResponse is the outcome of Search and SearchAsync
+type SearchResponse struct {
+	// Request is the last known state of the search request
+	Request *SearchRequest
+
+	// Results are the search results, nil for SearchAsync
+	Results []SearchResult
+}
+
+// GetSearchRequests retrieves a paginated list of search requests
+func (c *Client) GetSearchRequests(ctx context.Context, page, pageSize int) (*SearchRequestList, error) {
+	queryParams := url.Values{}
+	queryParams.Set("page", strconv.Itoa(page))
+	queryParams.Set("page_size", strconv.Itoa(pageSize))
+
+	return c.getSearchRequestsPage(ctx, queryParams)
+}
+
+// IterateSearchRequests returns an iterator over all search requests
+func (c *Client) IterateSearchRequests(opts IteratorOptions) *Iterator[SearchRequest] {
+	return newIterator(url.Values{}, opts, func(ctx context.Context, query url.Values) ([]SearchRequest, *string, error) {
+		list, err := c.getSearchRequestsPage(ctx, query)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Results, list.Next, nil
+	})
+}
+
+// getSearchRequestsPage retrieves one page of search requests
+func (c *Client) getSearchRequestsPage(ctx context.Context, query url.Values) (*SearchRequestList, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, c.apiPath("core/search/"), query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result SearchRequestList
+	if err := c.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetSearchRequest retrieves a specific search request by ID
+func (c *Client) GetSearchRequest(ctx context.Context, id string) (*SearchRequest, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, c.apiPath("core/search/%s/", id), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result SearchRequest
+	if err := c.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// CreateSearchRequest creates a new search request
+func (c *Client) CreateSearchRequest(ctx context.Context, input CreateSearchRequestInput) (*SearchRequest, error) {
+	// Validate input
+	switch {
+	case input.Query == "":
+		return nil, &ValidationError{Field: "query", Message: "query is required"}
+	case input.ResultLimit < 0:
+		return nil, &ValidationError{Field: "result_limit", Message: "result limit must not be negative"}
+	}
+
+	var header http.Header
+	if input.IdempotencyKey != "" {
+		header = http.Header{}
+		header.Set(IdempotencyKeyHeader, input.IdempotencyKey)
+	}
+
+	resp, err := c.doRequestWithHeader(ctx, http.MethodPost, c.apiPath("core/search/"), nil, input, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var result SearchRequest
+	if err := c.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// StopSearchRequest stops a specific search request
+func (c *Client) StopSearchRequest(ctx context.Context, id string) error {
+	resp, err := c.doRequest(ctx, http.MethodDelete, c.apiPath("core/search/%s/", id), nil, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.processResponse(resp, nil)
+}
+
+// WatchSearchRequest monitors the status of a search request, like
+// WatchCrawlRequest. opts may be nil; Download does not apply.
+func (c *Client) WatchSearchRequest(ctx context.Context, id string, opts *MonitorOptions) (*CrawlMonitor, error) {
+	if opts == nil {
+		opts = &MonitorOptions{}
+	}
+
+	return c.watch(ctx, &statusMonitor{
+		client:  c,
+		kind:    "search request",
+		id:      id,
+		path:    c.apiPath("core/search/%s/status/", id),
+		onError: opts.OnError,
+		get: func(ctx context.Context) (interface{}, error) {
+			return c.GetSearchRequest(ctx, id)
+		},
+	})
+}
+
+// GetSearchResults retrieves the results of a finished search request
+func (c *Client) GetSearchResults(ctx context.Context, id string) ([]SearchResult, error) {
+	request, err := c.GetSearchRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.searchResultsOf(ctx, request)
+}
+
+// SearchAsync creates a search request and returns without waiting for it.
+// Follow it with WatchSearchRequest and collect the results with
+// GetSearchResults.
+func (c *Client) SearchAsync(ctx context.Context, input CreateSearchRequestInput) (*SearchResponse, error) {
+	request, err := c.CreateSearchRequest(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Infof("Search request created with UUID: %s, Status: %s", request.UUID, request.Status)
+	return &SearchResponse{Request: request}, nil
+}
+
+// Search creates a search request, waits for it to finish and returns its
+// results. Progress is followed over the event stream, falling back to
+// polling as configured by the client's reconnect policy.
+func (c *Client) Search(ctx context.Context, input CreateSearchRequestInput) (*SearchResponse, error) {
+	response, err := c.SearchAsync(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	request := response.Request
+
+	monitor, err := c.WatchSearchRequest(ctx, request.UUID, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer monitor.Close()
+
+	for event := range monitor.Events() {
+		c.logger.Debugf("Received %s event for search request %s", event.Type, request.UUID)
+	}
+	if err := monitor.Err(); err != nil {
+		return nil, fmt.Errorf("monitoring search request %s: %w", request.UUID, err)
+	}
+
+	request, err = c.GetSearchRequest(ctx, request.UUID)
+	if err != nil {
+		return nil, err
+	}
+	if !request.Status.IsSuccess() {
+		return nil, fmt.Errorf("search request failed with status: %s", request.Status)
+	}
+
+	results, err := c.searchResultsOf(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearchResponse{Request: request, Results: results}, nil
+}
+
+// searchResultsOf returns the results of a request, downloading them when the
+// API returned them as a URL
+func (c *Client) searchResultsOf(ctx context.Context, request *SearchRequest) ([]SearchResult, error) {
+	if len(request.Result) == 0 || string(request.Result) == "null" {
+		return nil, fmt.Errorf("search request %s has no result (status: %s)", request.UUID, request.Status)
+	}
+
+	var results []SearchResult
+	var resultURL string
+	if err := json.Unmarshal(request.Result, &resultURL); err == nil {
+		c.logger.Debugf("Fetching results of search request %s", request.UUID)
+		if err := c.fetchStored(ctx, resultURL, &results); err != nil {
+			return nil, fmt.Errorf("failed to fetch search results: %w", err)
+		}
+	} else if err := json.Unmarshal(request.Result, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode search results: %w", err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,236 @@
+package watercrawl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClient_CreateSearchRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/core/search/" {
+			t.Errorf("Expected POST /api/v1/core/search/, got %s %s", r.Method, r.URL.Path)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		want := map[string]interface{}{
+			"query": "watercrawl",
+			"search_options": map[string]interface{}{
+				"language":   "en",
+				"country":    "us",
+				"time_renge": "week",
+				"depth":      "advanced",
+				"safe":       true,
+			},
+			"result_limit": float64(3),
+		}
+		if !reflect.DeepEqual(body, want) {
+			t.Errorf("request body = %v, want %v", body, want)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"uuid":"search-uuid","query":"watercrawl","status":"new","result_limit":3,"search_options":{"depth":"advanced"}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	request, err := client.CreateSearchRequest(context.Background(), CreateSearchRequestInput{
+		Query: "watercrawl",
+		Options: SearchOptions{
+			Language:  "en",
+			Country:   "us",
+			TimeRange: SearchTimeRangeWeek,
+			Depth:     SearchDepthAdvanced,
+			Extra:     map[string]interface{}{"safe": true},
+		},
+		ResultLimit: 3,
+	})
+	if err != nil {
+		t.Fatalf("CreateSearchRequest() error = %v", err)
+	}
+	if request.UUID != "search-uuid" || request.Status != CrawlStatusNew || request.Options.Depth != SearchDepthAdvanced {
+		t.Errorf("CreateSearchRequest() = %+v", request)
+	}
+}
+
+func TestClient_CreateSearchRequest_Validation(t *testing.T) {
+	client := NewClient("test-key", "")
+
+	tests := []struct {
+		name  string
+		input CreateSearchRequestInput
+	}{
+		{"missing query", CreateSearchRequestInput{}},
+		{"negative result limit", CreateSearchRequestInput{Query: "watercrawl", ResultLimit: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.CreateSearchRequest(context.Background(), tt.input)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("CreateSearchRequest() error = %v, want *ValidationError", err)
+			}
+		})
+	}
+}
+
+func TestClient_SearchRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/core/search/":
+			if r.URL.Query().Get("page") != "2" || r.URL.Query().Get("page_size") != "5" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"count":1,"results":[{"uuid":"search-uuid","status":"finished"}]}`)
+		case "GET /api/v1/core/search/search-uuid/":
+			fmt.Fprint(w, `{"uuid":"search-uuid","status":"running"}`)
+		case "DELETE /api/v1/core/search/search-uuid/":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+	ctx := context.Background()
+
+	list, err := client.GetSearchRequests(ctx, 2, 5)
+	if err != nil {
+		t.Fatalf("GetSearchRequests() error = %v", err)
+	}
+	if list.Count != 1 || len(list.Results) != 1 || list.Results[0].Status != CrawlStatusFinished {
+		t.Errorf("GetSearchRequests() = %+v", list)
+	}
+
+	request, err := client.GetSearchRequest(ctx, "search-uuid")
+	if err != nil {
+		t.Fatalf("GetSearchRequest() error = %v", err)
+	}
+	if request.Status != CrawlStatusRunning {
+		t.Errorf("GetSearchRequest().Status = %v, want %v", request.Status, CrawlStatusRunning)
+	}
+
+	if err := client.StopSearchRequest(ctx, "search-uuid"); err != nil {
+		t.Errorf("StopSearchRequest() error = %v", err)
+	}
+}
+
+func TestClient_Search(t *testing.T) {
+	results := []SearchResult{
+		{URL: "https://watercrawl.dev/", Title: "WaterCrawl", Description: "Web crawling", Order: 1},
+		{URL: "https://github.com/watercrawl", Title: "GitHub", Order: 2},
+	}
+
+	tests := []struct {
+		name   string
+		result func(serverURL string) interface{}
+	}{
+		{
+			name:   "inline",
+			result: func(string) interface{} { return results },
+		},
+		{
+			name:   "by url",
+			result: func(serverURL string) interface{} { return serverURL + "/storage/search.json" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/v1/core/search/":
+					w.Header().Set("Content-Type", "application/json")
+					fmt.Fprint(w, `{"uuid":"search-uuid","status":"new"}`)
+				case "/api/v1/core/search/search-uuid/status/":
+					w.Header().Set("Content-Type", "text/event-stream")
+					fmt.Fprint(w, "data: {\"type\":\"state\",\"data\":{\"uuid\":\"search-uuid\",\"status\":\"running\"}}\n\n")
+					fmt.Fprint(w, "data: {\"type\":\"state\",\"data\":{\"uuid\":\"search-uuid\",\"status\":\"finished\"}}\n\n")
+				case "/api/v1/core/search/search-uuid/":
+					w.Header().Set("Content-Type", "application/json")
+					request := map[string]interface{}{
+						"uuid":   "search-uuid",
+						"status": "finished",
+						"result": tt.result(server.URL),
+					}
+					if err := json.NewEncoder(w).Encode(request); err != nil {
+						t.Errorf("Failed to encode response: %v", err)
+					}
+				case "/storage/search.json":
+					if r.Header.Get("X-API-Key") != "" {
+						t.Errorf("API key sent to result storage")
+					}
+					w.Header().Set("Content-Type", "application/json")
+					if err := json.NewEncoder(w).Encode(results); err != nil {
+						t.Errorf("Failed to encode results: %v", err)
+					}
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient("test-key", server.URL+"/")
+
+			response, err := client.Search(context.Background(), CreateSearchRequestInput{Query: "watercrawl"})
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if response.Request.Status != CrawlStatusFinished {
+				t.Errorf("Search().Request.Status = %v, want %v", response.Request.Status, CrawlStatusFinished)
+			}
+			if !reflect.DeepEqual(response.Results, results) {
+				t.Errorf("Search().Results = %+v, want %+v", response.Results, results)
+			}
+		})
+	}
+}
+
+func TestClient_SearchAsync(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/core/search/" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"uuid":"search-uuid","status":"new"}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	response, err := client.SearchAsync(context.Background(), CreateSearchRequestInput{Query: "watercrawl"})
+	if err != nil {
+		t.Fatalf("SearchAsync() error = %v", err)
+	}
+	if response.Request.UUID != "search-uuid" || response.Results != nil {
+		t.Errorf("SearchAsync() = %+v, want the request without results", response)
+	}
+}
+
+func TestClient_GetSearchResults_NoResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"uuid":"search-uuid","status":"running","result":null}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	if _, err := client.GetSearchResults(context.Background(), "search-uuid"); err == nil {
+		t.Errorf("GetSearchResults() error = nil, want error")
+	}
+}
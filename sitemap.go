@@ -0,0 +1,285 @@
+package watercrawl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// SitemapOptions configures the discovery of a site's URLs
+type SitemapOptions struct {
+	// IncludeSubdomains also discovers URLs on subdomains of the site
+	IncludeSubdomains *bool `json:"include_subdomains,omitempty"`
+	// IgnoreSitemapXML skips the site's sitemap.xml and discovers URLs by crawling only
+	IgnoreSitemapXML *bool `json:"ignore_sitemap_xml,omitempty"`
+	// Search keeps only URLs matching the search term
+	Search       string   `json:"search,omitempty"`
+	IncludePaths []string `json:"include_paths,omitempty"`
+	ExcludePaths []string `json:"exclude_paths,omitempty"`
+
+	// Extra holds options not covered above.
+	// Typed fields take precedence over Extra keys of the same name.
+	Extra map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON implements json.Marshaler, merging Extra into the output
+func (o SitemapOptions) MarshalJSON() ([]byte, error) {
+	type sitemapOptions SitemapOptions
+	return marshalWithExtra(sitemapOptions(o), o.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, collecting unknown keys into Extra
+func (o *SitemapOptions) UnmarshalJSON(data []byte) error {
+	type sitemapOptions SitemapOptions
+	var v sitemapOptions
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*o = SitemapOptions(v)
+	o.Extra = extra
+	return nil
+}
+
+// SitemapRequest represents a sitemap generation request
+type SitemapRequest struct {
+	UUID    string         `json:"uuid"`
+	URL     string         `json:"url"`
+	Status  CrawlStatus    `json:"status"`
+	Options SitemapOptions `json:"options"`
+	// Result is the sitemap, inline or as the URL of a JSON document;
+	// use Client.GetSitemap to retrieve it
+	Result    json.RawMessage `json:"result,omitempty"`
+	CreatedAt Timestamp       `json:"created_at"`
+	UpdatedAt Timestamp       `json:"updated_at"`
+}
+
+// SitemapRequestList represents a paginated list of sitemap requests
+type SitemapRequestList struct {
+	Count    int              `json:"count"`
+	Next     *string          `json:"next"`
+	Previous *string          `json:"previous"`
+	Results  []SitemapRequest `json:"results"`
+}
+
+// CreateSitemapRequestInput represents the input for creating a sitemap request
+type CreateSitemapRequestInput struct {
+	URL string `json:"url,omitempty"`
+	// CrawlRequest builds the sitemap from the pages of a crawl request
+	// instead of URL
+	CrawlRequest string         `json:"crawl_request,omitempty"`
+	Options      SitemapOptions `json:"options"`
+
+	// IdempotencyKey, if set, is sent as the Idempotency-Key header and makes
+	// the request eligible for retries
+	IdempotencyKey string `json:"-"`
+}
+
+// GetSitemapRequests retrieves a paginated list of sitemap requests
+func (c *Client) GetSitemapRequests(ctx context.Context, page, pageSize int) (*SitemapRequestList, error) {
+	queryParams := url.Values{}
+	queryParams.Set("page", strconv.Itoa(page))
+	queryParams.Set("page_size", strconv.Itoa(pageSize))
+
+	return c.getSitemapRequestsPage(ctx, queryParams)
+}
+
+// IterateSitemapRequests returns an iterator over all sitemap requests
+func (c *Client) IterateSitemapRequests(opts IteratorOptions) *Iterator[SitemapRequest] {
+	return newIterator(url.Values{}, opts, func(ctx context.Context, query url.Values) ([]SitemapRequest, *string, error) {
+		list, err := c.getSitemapRequestsPage(ctx, query)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Results, list.Next, nil
+	})
+}
+
+// getSitemapRequestsPage retrieves one page of sitemap requests
+func (c *Client) getSitemapRequestsPage(ctx context.Context, query url.Values) (*SitemapRequestList, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, c.apiPath("core/sitemaps/"), query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result SitemapRequestList
+	if err := c.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetSitemapRequest retrieves a specific sitemap request by ID
+func (c *Client) GetSitemapRequest(ctx context.Context, id string) (*SitemapRequest, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, c.apiPath("core/sitemaps/%s/", id), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result SitemapRequest
+	if err := c.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// CreateSitemapRequest creates a new sitemap request
+func (c *Client) CreateSitemapRequest(ctx context.Context, input CreateSitemapRequestInput) (*SitemapRequest, error) {
+	// Validate input
+	switch {
+	case input.URL == "" && input.CrawlRequest == "":
+		return nil, &ValidationError{Field: "url", Message: "URL or crawl request is required"}
+	case input.URL != "":
+		if err := (URLs{input.URL}).Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	var header http.Header
+	if input.IdempotencyKey != "" {
+		header = http.Header{}
+		header.Set(IdempotencyKeyHeader, input.IdempotencyKey)
+	}
+
+	resp, err := c.doRequestWithHeader(ctx, http.MethodPost, c.apiPath("core/sitemaps/"), nil, input, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var result SitemapRequest
+	if err := c.processResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// StopSitemapRequest stops a specific sitemap request
+func (c *Client) StopSitemapRequest(ctx context.Context, id string) error {
+	resp, err := c.doRequest(ctx, http.MethodDelete, c.apiPath("core/sitemaps/%s/", id), nil, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.processResponse(resp, nil)
+}
+
+// WatchSitemapRequest monitors the status of a sitemap request, like
+// WatchCrawlRequest. opts may be nil; Download does not apply.
+func (c *Client) WatchSitemapRequest(ctx context.Context, id string, opts *MonitorOptions) (*CrawlMonitor, error) {
+	if opts == nil {
+		opts = &MonitorOptions{}
+	}
+
+	return c.watch(ctx, &statusMonitor{
+		client:  c,
+		kind:    "sitemap request",
+		id:      id,
+		path:    c.apiPath("core/sitemaps/%s/status/", id),
+		onError: opts.OnError,
+		get: func(ctx context.Context) (interface{}, error) {
+			return c.GetSitemapRequest(ctx, id)
+		},
+	})
+}
+
+// GetSitemap retrieves the sitemap generated by a finished sitemap request
+func (c *Client) GetSitemap(ctx context.Context, id string) (*Sitemap, error) {
+	request, err := c.GetSitemapRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.sitemapOf(ctx, request)
+}
+
+// GenerateSitemap creates a sitemap request, waits for it to finish and
+// returns the sitemap. Progress is followed over the event stream, falling
+// back to polling as configured by the client's reconnect policy.
+func (c *Client) GenerateSitemap(ctx context.Context, input CreateSitemapRequestInput) (*Sitemap, error) {
+	request, err := c.CreateSitemapRequest(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Infof("Sitemap request created with UUID: %s, Status: %s", request.UUID, request.Status)
+
+	monitor, err := c.WatchSitemapRequest(ctx, request.UUID, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer monitor.Close()
+
+	for event := range monitor.Events() {
+		c.logger.Debugf("Received %s event for sitemap request %s", event.Type, request.UUID)
+	}
+	if err := monitor.Err(); err != nil {
+		return nil, fmt.Errorf("monitoring sitemap request %s: %w", request.UUID, err)
+	}
+
+	request, err = c.GetSitemapRequest(ctx, request.UUID)
+	if err != nil {
+		return nil, err
+	}
+	if !request.Status.IsSuccess() {
+		return nil, fmt.Errorf("sitemap request failed with status: %s", request.Status)
+	}
+
+	return c.sitemapOf(ctx, request)
+}
+
+// sitemapOf returns the sitemap of a request, downloading it when the API
+// returned it as a URL
+func (c *Client) sitemapOf(ctx context.Context, request *SitemapRequest) (*Sitemap, error) {
+	if len(request.Result) == 0 || string(request.Result) == "null" {
+		return nil, fmt.Errorf("sitemap request %s has no result (status: %s)", request.UUID, request.Status)
+	}
+
+	var entries sitemapEntries
+	var resultURL string
+	if err := json.Unmarshal(request.Result, &resultURL); err == nil {
+		c.logger.Debugf("Fetching sitemap of %s", request.UUID)
+		if err := c.fetchStored(ctx, resultURL, &entries); err != nil {
+			return nil, fmt.Errorf("failed to fetch sitemap: %w", err)
+		}
+	} else if err := json.Unmarshal(request.Result, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode sitemap: %w", err)
+	}
+
+	return &Sitemap{URLs: []string(entries)}, nil
+}
+
+// sitemapEntries decodes a list of URLs, given as strings or as objects with
+// a "url" field
+type sitemapEntries []string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (e *sitemapEntries) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	urls := make([]string, 0, len(raw))
+	for i, item := range raw {
+		var u string
+		if err := json.Unmarshal(item, &u); err != nil {
+			var entry struct {
+				URL string `json:"url"`
+			}
+			if err := json.Unmarshal(item, &entry); err != nil || entry.URL == "" {
+				return fmt.Errorf("sitemap entry %d is neither a URL nor an object with a url", i)
+			}
+			u = entry.URL
+		}
+		urls = append(urls, u)
+	}
+
+	*e = urls
+	return nil
+}
@@ -0,0 +1,224 @@
+package watercrawl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClient_CreateSitemapRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/core/sitemaps/" {
+			t.Errorf("Expected POST /api/v1/core/sitemaps/, got %s %s", r.Method, r.URL.Path)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		want := map[string]interface{}{
+			"url": "https://example.com",
+			"options": map[string]interface{}{
+				"include_subdomains": true,
+				"search":             "docs",
+			},
+		}
+		if !reflect.DeepEqual(body, want) {
+			t.Errorf("request body = %v, want %v", body, want)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"uuid":"sitemap-uuid","url":"https://example.com","status":"new","options":{"search":"docs"}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	request, err := client.CreateSitemapRequest(context.Background(), CreateSitemapRequestInput{
+		URL:     "https://example.com",
+		Options: SitemapOptions{IncludeSubdomains: Bool(true), Search: "docs"},
+	})
+	if err != nil {
+		t.Fatalf("CreateSitemapRequest() error = %v", err)
+	}
+	if request.UUID != "sitemap-uuid" || request.Status != CrawlStatusNew || request.Options.Search != "docs" {
+		t.Errorf("CreateSitemapRequest() = %+v", request)
+	}
+}
+
+func TestClient_CreateSitemapRequest_Validation(t *testing.T) {
+	client := NewClient("test-key", "")
+
+	tests := []struct {
+		name  string
+		input CreateSitemapRequestInput
+	}{
+		{"missing url", CreateSitemapRequestInput{}},
+		{"relative url", CreateSitemapRequestInput{URL: "example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.CreateSitemapRequest(context.Background(), tt.input)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("CreateSitemapRequest() error = %v, want *ValidationError", err)
+			}
+		})
+	}
+}
+
+func TestClient_SitemapRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/core/sitemaps/":
+			if r.URL.Query().Get("page") != "2" || r.URL.Query().Get("page_size") != "5" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"count":1,"results":[{"uuid":"sitemap-uuid","status":"finished"}]}`)
+		case "GET /api/v1/core/sitemaps/sitemap-uuid/":
+			fmt.Fprint(w, `{"uuid":"sitemap-uuid","status":"running"}`)
+		case "DELETE /api/v1/core/sitemaps/sitemap-uuid/":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+	ctx := context.Background()
+
+	list, err := client.GetSitemapRequests(ctx, 2, 5)
+	if err != nil {
+		t.Fatalf("GetSitemapRequests() error = %v", err)
+	}
+	if list.Count != 1 || len(list.Results) != 1 || list.Results[0].Status != CrawlStatusFinished {
+		t.Errorf("GetSitemapRequests() = %+v", list)
+	}
+
+	request, err := client.GetSitemapRequest(ctx, "sitemap-uuid")
+	if err != nil {
+		t.Fatalf("GetSitemapRequest() error = %v", err)
+	}
+	if request.Status != CrawlStatusRunning {
+		t.Errorf("GetSitemapRequest().Status = %v, want %v", request.Status, CrawlStatusRunning)
+	}
+
+	if err := client.StopSitemapRequest(ctx, "sitemap-uuid"); err != nil {
+		t.Errorf("StopSitemapRequest() error = %v", err)
+	}
+}
+
+func TestClient_GenerateSitemap(t *testing.T) {
+	urls := []string{"https://example.com/", "https://example.com/docs"}
+
+	tests := []struct {
+		name   string
+		result func(serverURL string) interface{}
+	}{
+		{
+			name:   "inline",
+			result: func(string) interface{} { return urls },
+		},
+		{
+			name:   "by url",
+			result: func(serverURL string) interface{} { return serverURL + "/storage/sitemap.json" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/v1/core/sitemaps/":
+					w.Header().Set("Content-Type", "application/json")
+					fmt.Fprint(w, `{"uuid":"sitemap-uuid","status":"new"}`)
+				case "/api/v1/core/sitemaps/sitemap-uuid/status/":
+					w.Header().Set("Content-Type", "text/event-stream")
+					fmt.Fprint(w, "data: {\"type\":\"state\",\"data\":{\"uuid\":\"sitemap-uuid\",\"status\":\"running\"}}\n\n")
+					fmt.Fprint(w, "data: {\"type\":\"state\",\"data\":{\"uuid\":\"sitemap-uuid\",\"status\":\"finished\"}}\n\n")
+				case "/api/v1/core/sitemaps/sitemap-uuid/":
+					w.Header().Set("Content-Type", "application/json")
+					request := map[string]interface{}{
+						"uuid":   "sitemap-uuid",
+						"status": "finished",
+						"result": tt.result(server.URL),
+					}
+					if err := json.NewEncoder(w).Encode(request); err != nil {
+						t.Errorf("Failed to encode response: %v", err)
+					}
+				case "/storage/sitemap.json":
+					if r.Header.Get("X-API-Key") != "" {
+						t.Errorf("API key sent to result storage")
+					}
+					w.Header().Set("Content-Type", "application/json")
+					fmt.Fprint(w, `[{"url":"https://example.com/"},{"url":"https://example.com/docs"}]`)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient("test-key", server.URL+"/")
+
+			sitemap, err := client.GenerateSitemap(context.Background(), CreateSitemapRequestInput{URL: "https://example.com"})
+			if err != nil {
+				t.Fatalf("GenerateSitemap() error = %v", err)
+			}
+			if !reflect.DeepEqual(sitemap.URLs, urls) {
+				t.Errorf("GenerateSitemap().URLs = %v, want %v", sitemap.URLs, urls)
+			}
+		})
+	}
+}
+
+func TestClient_GetSitemap_NoResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"uuid":"sitemap-uuid","status":"running","result":null}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	if _, err := client.GetSitemap(context.Background(), "sitemap-uuid"); err == nil {
+		t.Errorf("GetSitemap() error = nil, want error")
+	}
+}
+
+func TestSitemapEntries_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{"strings", `["https://a.com/","https://a.com/b"]`, []string{"https://a.com/", "https://a.com/b"}, false},
+		{"objects", `[{"url":"https://a.com/","title":"A"}]`, []string{"https://a.com/"}, false},
+		{"empty", `[]`, []string{}, false},
+		{"object without url", `[{"title":"A"}]`, nil, true},
+		{"not a list", `{"urls":[]}`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got sitemapEntries
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual([]string(got), tt.want) {
+				t.Errorf("UnmarshalJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
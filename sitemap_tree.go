@@ -0,0 +1,185 @@
+package watercrawl
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Sitemap is the list of URLs discovered on a site, with renderings as a
+// tree, a graph and Markdown
+type Sitemap struct {
+	URLs []string `json:"urls"`
+}
+
+// SitemapNode is a node of the sitemap tree: a site at the root, a path
+// segment below it
+type SitemapNode struct {
+	// Name is the host for roots and the path segment otherwise, followed by
+	// the query string for URLs that have one
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Listed is false for nodes that are only parents of listed URLs
+	Listed   bool           `json:"listed"`
+	Children []*SitemapNode `json:"children,omitempty"`
+}
+
+// SitemapGraph is the sitemap as a graph of URLs linked to their parent path
+type SitemapGraph struct {
+	Nodes []SitemapGraphNode `json:"nodes"`
+	Edges []SitemapGraphEdge `json:"edges"`
+}
+
+// SitemapGraphNode is a node of SitemapGraph, identified by its URL
+type SitemapGraphNode struct {
+	ID     string `json:"id"`
+	Label  string `json:"label"`
+	Listed bool   `json:"listed"`
+}
+
+// SitemapGraphEdge links a parent node to a child node of SitemapGraph
+type SitemapGraphEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// Tree returns the URLs nested by path, with one root per site sorted by
+// name. URLs that cannot be parsed as absolute URLs are left out.
+func (s *Sitemap) Tree() []*SitemapNode {
+	var roots []*SitemapNode
+	index := map[string]*SitemapNode{}
+
+	child := func(parent *SitemapNode, name, nodeURL string) *SitemapNode {
+		if node, ok := index[nodeURL]; ok {
+			return node
+		}
+		node := &SitemapNode{Name: name, URL: nodeURL}
+		index[nodeURL] = node
+		if parent == nil {
+			roots = append(roots, node)
+		} else {
+			parent.Children = append(parent.Children, node)
+		}
+		return node
+	}
+
+	for _, raw := range s.URLs {
+		u, err := url.Parse(raw)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			continue
+		}
+		u.Fragment = ""
+
+		origin := u.Scheme + "://" + u.Host
+		node := child(nil, u.Host, origin+"/")
+
+		nodeURL := origin
+		segments := splitPath(u.EscapedPath())
+		for i, segment := range segments {
+			nodeURL += "/" + segment
+			name := segment
+			if unescaped, err := url.PathUnescape(segment); err == nil {
+				name = unescaped
+			}
+			if i == len(segments)-1 && u.RawQuery != "" {
+				name += "?" + u.RawQuery
+				nodeURL += "?" + u.RawQuery
+			}
+			node = child(node, name, nodeURL)
+		}
+		if len(segments) == 0 && u.RawQuery != "" {
+			node = child(node, "?"+u.RawQuery, origin+"/?"+u.RawQuery)
+		}
+
+		node.Listed = true
+	}
+
+	sortNodes(roots)
+	return roots
+}
+
+// Graph returns the sitemap tree as nodes and parent to child edges
+func (s *Sitemap) Graph() *SitemapGraph {
+	graph := &SitemapGraph{
+		Nodes: []SitemapGraphNode{},
+		Edges: []SitemapGraphEdge{},
+	}
+
+	var walk func(parent, node *SitemapNode)
+	walk = func(parent, node *SitemapNode) {
+		graph.Nodes = append(graph.Nodes, SitemapGraphNode{ID: node.URL, Label: node.Name, Listed: node.Listed})
+		if parent != nil {
+			graph.Edges = append(graph.Edges, SitemapGraphEdge{Source: parent.URL, Target: node.URL})
+		}
+		for _, child := range node.Children {
+			walk(node, child)
+		}
+	}
+	for _, root := range s.Tree() {
+		walk(nil, root)
+	}
+
+	return graph
+}
+
+// Markdown renders the sitemap tree as a nested Markdown list, linking
+// listed URLs
+func (s *Sitemap) Markdown() string {
+	var b strings.Builder
+
+	var walk func(node *SitemapNode, depth int)
+	walk = func(node *SitemapNode, depth int) {
+		b.WriteString(strings.Repeat("  ", depth))
+		b.WriteString("- ")
+		if node.Listed {
+			b.WriteString("[" + escapeMarkdownText(node.Name) + "](" + escapeMarkdownURL(node.URL) + ")")
+		} else {
+			b.WriteString(escapeMarkdownText(node.Name))
+		}
+		b.WriteString("\n")
+		for _, child := range node.Children {
+			walk(child, depth+1)
+		}
+	}
+	for _, root := range s.Tree() {
+		walk(root, 0)
+	}
+
+	return b.String()
+}
+
+// splitPath returns the non-empty segments of a URL path
+func splitPath(path string) []string {
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// sortNodes sorts nodes and their descendants by name
+func sortNodes(nodes []*SitemapNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	for _, node := range nodes {
+		sortNodes(node.Children)
+	}
+}
+
+// markdownTextEscaper escapes characters with a meaning in Markdown link text
+var markdownTextEscaper = strings.NewReplacer(
+	`\`, `\\`, `[`, `\[`, `]`, `\]`, `*`, `\*`, `_`, `\_`, "`", "\\`",
+)
+
+// escapeMarkdownText escapes s for use as Markdown text
+func escapeMarkdownText(s string) string {
+	return markdownTextEscaper.Replace(s)
+}
+
+// escapeMarkdownURL escapes the characters that would end a Markdown link destination
+func escapeMarkdownURL(s string) string {
+	return strings.NewReplacer("(", "%28", ")", "%29", " ", "%20").Replace(s)
+}
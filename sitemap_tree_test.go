@@ -0,0 +1,79 @@
+package watercrawl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+var testSitemap = &Sitemap{URLs: []string{
+	"https://example.com/",
+	"https://example.com/docs/api/v1",
+	"https://example.com/docs",
+	"https://example.com/blog?page=2",
+	"https://example.com/docs#intro",
+	"https://blog.example.com/a_[b]",
+	"not a url",
+}}
+
+func TestSitemap_Tree(t *testing.T) {
+	raw, err := json.Marshal(testSitemap.Tree())
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := `[` +
+		`{"name":"blog.example.com","url":"https://blog.example.com/","listed":false,"children":[` +
+		`{"name":"a_[b]","url":"https://blog.example.com/a_[b]","listed":true}]},` +
+		`{"name":"example.com","url":"https://example.com/","listed":true,"children":[` +
+		`{"name":"blog?page=2","url":"https://example.com/blog?page=2","listed":true},` +
+		`{"name":"docs","url":"https://example.com/docs","listed":true,"children":[` +
+		`{"name":"api","url":"https://example.com/docs/api","listed":false,"children":[` +
+		`{"name":"v1","url":"https://example.com/docs/api/v1","listed":true}]}]}]}]`
+	if string(raw) != want {
+		t.Errorf("Tree() =\n%s\nwant\n%s", raw, want)
+	}
+}
+
+func TestSitemap_Graph(t *testing.T) {
+	graph := testSitemap.Graph()
+
+	if len(graph.Nodes) != 7 {
+		t.Errorf("Graph() nodes = %d, want 7", len(graph.Nodes))
+	}
+	if len(graph.Edges) != 5 {
+		t.Errorf("Graph() edges = %d, want 5", len(graph.Edges))
+	}
+
+	want := SitemapGraphEdge{Source: "https://example.com/docs/api", Target: "https://example.com/docs/api/v1"}
+	found := false
+	for _, edge := range graph.Edges {
+		if edge == want {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Graph() edges = %v, missing %v", graph.Edges, want)
+	}
+
+	empty, err := json.Marshal((&Sitemap{}).Graph())
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(empty) != `{"nodes":[],"edges":[]}` {
+		t.Errorf("empty Graph() = %s, want empty lists", empty)
+	}
+}
+
+func TestSitemap_Markdown(t *testing.T) {
+	want := `- blog.example.com
+  - [a\_\[b\]](https://blog.example.com/a_[b])
+- [example.com](https://example.com/)
+  - [blog?page=2](https://example.com/blog?page=2)
+  - [docs](https://example.com/docs)
+    - api
+      - [v1](https://example.com/docs/api/v1)
+`
+	if got := testSitemap.Markdown(); got != want {
+		t.Errorf("Markdown() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,171 @@
+package watercrawl
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SSEEvent is an event dispatched by an SSEDecoder
+type SSEEvent struct {
+	// ID is the last event ID at the time the event was dispatched
+	ID string
+	// Event is the event type, "message" unless the stream set one
+	Event string
+	// Data is the event data, with multiple data lines joined by newlines
+	Data string
+}
+
+// SSEDecoder decodes a text/event-stream as specified by the WHATWG HTML
+// standard: events are dispatched on blank lines, data lines are concatenated,
+// comments are ignored, and LF, CR and CRLF line endings as well as a leading
+// byte order mark are accepted.
+type SSEDecoder struct {
+	r           *bufio.Reader
+	started     bool
+	lastEventID string
+	retry       time.Duration
+	eventType   string
+	data        bytes.Buffer
+	line        bytes.Buffer
+}
+
+// NewSSEDecoder creates a decoder reading from r
+func NewSSEDecoder(r io.Reader) *SSEDecoder {
+	return &SSEDecoder{r: bufio.NewReader(r)}
+}
+
+// Next returns the next event. It returns io.EOF at the end of the stream;
+// an event not terminated by a blank line before the end is discarded.
+func (d *SSEDecoder) Next() (*SSEEvent, error) {
+	for {
+		line, err := d.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(line) == 0 {
+			if event := d.dispatch(); event != nil {
+				return event, nil
+			}
+			continue
+		}
+
+		d.processLine(line)
+	}
+}
+
+// LastEventID returns the last event ID set by the stream, to be sent as
+// the Last-Event-ID header when reconnecting
+func (d *SSEDecoder) LastEventID() string {
+	return d.lastEventID
+}
+
+// Retry returns the reconnection time set by the stream, or 0 if none was set
+func (d *SSEDecoder) Retry() time.Duration {
+	return d.retry
+}
+
+// readLine reads a line terminated by LF, CR or CRLF, without its terminator.
+// The returned slice is only valid until the next call.
+func (d *SSEDecoder) readLine() ([]byte, error) {
+	d.line.Reset()
+	for {
+		b, err := d.r.ReadByte()
+		if err != nil {
+			// A trailing line without terminator is incomplete and discarded
+			return nil, err
+		}
+
+		switch b {
+		case '\n':
+			return d.stripBOM(d.line.Bytes()), nil
+		case '\r':
+			if next, err := d.r.Peek(1); err == nil && next[0] == '\n' {
+				_, _ = d.r.ReadByte()
+			}
+			return d.stripBOM(d.line.Bytes()), nil
+		default:
+			d.line.WriteByte(b)
+		}
+	}
+}
+
+// stripBOM removes a byte order mark from the first line of the stream
+func (d *SSEDecoder) stripBOM(line []byte) []byte {
+	if d.started {
+		return line
+	}
+	d.started = true
+	return bytes.TrimPrefix(line, []byte("\xEF\xBB\xBF"))
+}
+
+// processLine handles a non-empty line
+func (d *SSEDecoder) processLine(line []byte) {
+	if line[0] == ':' {
+		// Comment, typically a heartbeat
+		return
+	}
+
+	field, value := string(line), ""
+	if i := bytes.IndexByte(line, ':'); i >= 0 {
+		field = string(line[:i])
+		value = strings.TrimPrefix(string(line[i+1:]), " ")
+	}
+
+	switch field {
+	case "event":
+		d.eventType = value
+	case "data":
+		d.data.WriteString(value)
+		d.data.WriteByte('\n')
+	case "id":
+		if !strings.ContainsRune(value, 0) {
+			d.lastEventID = value
+		}
+	case "retry":
+		if isASCIIDigits(value) {
+			if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+				d.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
+
+// dispatch returns the buffered event, or nil if there is no data
+func (d *SSEDecoder) dispatch() *SSEEvent {
+	defer func() {
+		d.eventType = ""
+		d.data.Reset()
+	}()
+
+	if d.data.Len() == 0 {
+		return nil
+	}
+
+	event := &SSEEvent{
+		ID:    d.lastEventID,
+		Event: d.eventType,
+		Data:  strings.TrimSuffix(d.data.String(), "\n"),
+	}
+	if event.Event == "" {
+		event.Event = "message"
+	}
+	return event
+}
+
+// isASCIIDigits reports whether s is a non-empty string of ASCII digits
+func isASCIIDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,146 @@
+package watercrawl
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// decodeAll decodes every event of input
+func decodeAll(t testing.TB, input string) ([]SSEEvent, *SSEDecoder) {
+	decoder := NewSSEDecoder(strings.NewReader(input))
+	var events []SSEEvent
+	for {
+		event, err := decoder.Next()
+		if errors.Is(err, io.EOF) {
+			return events, decoder
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		events = append(events, *event)
+	}
+}
+
+func TestSSEDecoder(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      []SSEEvent
+		wantID    string
+		wantRetry time.Duration
+	}{
+		{
+			name:  "single data line",
+			input: "data: {\"type\":\"state\"}\n\n",
+			want:  []SSEEvent{{Event: "message", Data: `{"type":"state"}`}},
+		},
+		{
+			name:  "multi-line data",
+			input: "data: first\ndata:second\ndata\n\n",
+			want:  []SSEEvent{{Event: "message", Data: "first\nsecond\n"}},
+		},
+		{
+			name:   "event, id and retry fields",
+			input:  "event: progress\nid: 42\nretry: 1500\ndata: 50\n\n",
+			want:   []SSEEvent{{ID: "42", Event: "progress", Data: "50"}},
+			wantID: "42", wantRetry: 1500 * time.Millisecond,
+		},
+		{
+			name:  "comments and heartbeats",
+			input: ": heartbeat\n\n:\ndata: x\n: keep-alive\n\n",
+			want:  []SSEEvent{{Event: "message", Data: "x"}},
+		},
+		{
+			name:  "CRLF and CR line endings",
+			input: "data: a\r\n\r\ndata: b\r\rdata: c\n\n",
+			want: []SSEEvent{
+				{Event: "message", Data: "a"},
+				{Event: "message", Data: "b"},
+				{Event: "message", Data: "c"},
+			},
+		},
+		{
+			name:  "byte order mark",
+			input: "\xEF\xBB\xBFdata: bom\n\n",
+			want:  []SSEEvent{{Event: "message", Data: "bom"}},
+		},
+		{
+			name:  "event type resets after dispatch",
+			input: "event: result\ndata: 1\n\ndata: 2\n\n",
+			want: []SSEEvent{
+				{Event: "result", Data: "1"},
+				{Event: "message", Data: "2"},
+			},
+		},
+		{
+			name:   "id persists and NULL ids are ignored",
+			input:  "id: 1\ndata: a\n\nid: bad\x00id\ndata: b\n\n",
+			want:   []SSEEvent{{ID: "1", Event: "message", Data: "a"}, {ID: "1", Event: "message", Data: "b"}},
+			wantID: "1",
+		},
+		{
+			name:  "invalid retry is ignored",
+			input: "retry: 1s\ndata: a\n\n",
+			want:  []SSEEvent{{Event: "message", Data: "a"}},
+		},
+		{
+			name:  "event without data is not dispatched",
+			input: "event: state\n\ndata: a\n\n",
+			want:  []SSEEvent{{Event: "message", Data: "a"}},
+		},
+		{
+			name:  "incomplete event at end of stream is discarded",
+			input: "data: a\n\ndata: b\n",
+			want:  []SSEEvent{{Event: "message", Data: "a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, decoder := decodeAll(t, tt.input)
+			if !reflect.DeepEqual(events, tt.want) {
+				t.Errorf("events = %#v, want %#v", events, tt.want)
+			}
+			if decoder.LastEventID() != tt.wantID {
+				t.Errorf("LastEventID() = %q, want %q", decoder.LastEventID(), tt.wantID)
+			}
+			if decoder.Retry() != tt.wantRetry {
+				t.Errorf("Retry() = %v, want %v", decoder.Retry(), tt.wantRetry)
+			}
+		})
+	}
+}
+
+func FuzzSSEDecoder(f *testing.F) {
+	f.Add("data: {\"type\":\"state\"}\n\n")
+	f.Add("event: progress\r\nid: 1\r\nretry: 10\r\ndata: a\r\ndata: b\r\n\r\n")
+	f.Add("\xEF\xBB\xBF: comment\rdata\r\r")
+	f.Add("data:x\n\nid\nretry:\n")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		events, _ := decodeAll(t, input)
+
+		for _, event := range events {
+			for _, s := range []string{event.Event, event.ID} {
+				if strings.ContainsAny(s, "\r\n") {
+					t.Errorf("field %q contains a line break", s)
+				}
+			}
+			if strings.ContainsRune(event.Data, '\r') {
+				t.Errorf("data %q contains a carriage return", event.Data)
+			}
+		}
+
+		// Decoding must not depend on the line ending style
+		normalized := strings.ReplaceAll(input, "\r\n", "\n")
+		normalized = strings.ReplaceAll(normalized, "\r", "\n")
+		normalizedEvents, _ := decodeAll(t, normalized)
+		if !reflect.DeepEqual(events, normalizedEvents) {
+			t.Errorf("events for %q = %#v, normalized %#v", input, events, normalizedEvents)
+		}
+	})
+}
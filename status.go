@@ -0,0 +1,74 @@
+package watercrawl
+
+// CrawlStatus is the status of a crawl request.
+// Values not listed below are preserved as-is when decoding.
+type CrawlStatus string
+
+// Crawl statuses reported by the API
+const (
+	CrawlStatusNew        CrawlStatus = "new"
+	CrawlStatusRunning    CrawlStatus = "running"
+	CrawlStatusCancelling CrawlStatus = "cancelling"
+	CrawlStatusCanceled   CrawlStatus = "canceled"
+	CrawlStatusFailed     CrawlStatus = "failed"
+	CrawlStatusFinished   CrawlStatus = "finished"
+	// CrawlStatusCompleted is reported by older API versions instead of CrawlStatusFinished
+	CrawlStatusCompleted CrawlStatus = "completed"
+)
+
+// IsKnown reports whether s is one of the statuses declared by this package
+func (s CrawlStatus) IsKnown() bool {
+	switch s {
+	case CrawlStatusNew, CrawlStatusRunning, CrawlStatusCancelling, CrawlStatusCanceled,
+		CrawlStatusFailed, CrawlStatusFinished, CrawlStatusCompleted:
+		return true
+	}
+	return false
+}
+
+// IsTerminal reports whether the crawl has stopped and its status will not change
+func (s CrawlStatus) IsTerminal() bool {
+	switch s {
+	case CrawlStatusCanceled, CrawlStatusFailed, CrawlStatusFinished, CrawlStatusCompleted:
+		return true
+	}
+	return false
+}
+
+// IsSuccess reports whether the crawl finished successfully
+func (s CrawlStatus) IsSuccess() bool {
+	return s == CrawlStatusFinished || s == CrawlStatusCompleted
+}
+
+// String returns the status as sent by the API
+func (s CrawlStatus) String() string {
+	return string(s)
+}
+
+// EventType is the type of a message from the crawl status event stream.
+// Values not listed below are preserved as-is when decoding.
+type EventType string
+
+// Event types sent on the crawl status event stream
+const (
+	EventTypeState     EventType = "state"
+	EventTypeProgress  EventType = "progress"
+	EventTypeResult    EventType = "result"
+	EventTypeFeed      EventType = "feed"
+	EventTypeError     EventType = "error"
+	EventTypeCompleted EventType = "completed"
+)
+
+// IsKnown reports whether t is one of the event types declared by this package
+func (t EventType) IsKnown() bool {
+	switch t {
+	case EventTypeState, EventTypeProgress, EventTypeResult, EventTypeFeed, EventTypeError, EventTypeCompleted:
+		return true
+	}
+	return false
+}
+
+// String returns the event type as sent by the API
+func (t EventType) String() string {
+	return string(t)
+}
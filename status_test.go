@@ -0,0 +1,56 @@
+package watercrawl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCrawlStatus(t *testing.T) {
+	tests := []struct {
+		status       CrawlStatus
+		wantKnown    bool
+		wantTerminal bool
+		wantSuccess  bool
+	}{
+		{status: CrawlStatusNew, wantKnown: true},
+		{status: CrawlStatusRunning, wantKnown: true},
+		{status: CrawlStatusCancelling, wantKnown: true},
+		{status: CrawlStatusCanceled, wantKnown: true, wantTerminal: true},
+		{status: CrawlStatusFailed, wantKnown: true, wantTerminal: true},
+		{status: CrawlStatusFinished, wantKnown: true, wantTerminal: true, wantSuccess: true},
+		{status: CrawlStatusCompleted, wantKnown: true, wantTerminal: true, wantSuccess: true},
+		{status: CrawlStatus("paused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := tt.status.IsKnown(); got != tt.wantKnown {
+				t.Errorf("IsKnown() = %v, want %v", got, tt.wantKnown)
+			}
+			if got := tt.status.IsTerminal(); got != tt.wantTerminal {
+				t.Errorf("IsTerminal() = %v, want %v", got, tt.wantTerminal)
+			}
+			if got := tt.status.IsSuccess(); got != tt.wantSuccess {
+				t.Errorf("IsSuccess() = %v, want %v", got, tt.wantSuccess)
+			}
+		})
+	}
+}
+
+func TestStatus_UnmarshalUnknown(t *testing.T) {
+	var request CrawlRequest
+	if err := json.Unmarshal([]byte(`{"uuid":"test-uuid","status":"paused"}`), &request); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if request.Status != "paused" || request.Status.IsKnown() {
+		t.Errorf("Status = %q (known %v), want unknown %q", request.Status, request.Status.IsKnown(), "paused")
+	}
+
+	var event EventStreamMessage
+	if err := json.Unmarshal([]byte(`{"type":"heartbeat","data":null}`), &event); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if event.Type != "heartbeat" || event.Type.IsKnown() {
+		t.Errorf("Type = %q (known %v), want unknown %q", event.Type, event.Type.IsKnown(), "heartbeat")
+	}
+}
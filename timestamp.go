@@ -0,0 +1,66 @@
+package watercrawl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// timestampLayouts are the ISO-8601 variants accepted when decoding a Timestamp.
+// Fractional seconds are accepted by every layout.
+var timestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+// Timestamp is a time decoded from the API's ISO-8601 timestamps.
+// Timestamps without a zone are interpreted as UTC.
+type Timestamp struct {
+	time.Time
+}
+
+// ParseTimestamp parses an ISO-8601 timestamp as sent by the API
+func ParseTimestamp(value string) (Timestamp, error) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return Timestamp{Time: t}, nil
+		}
+	}
+	return Timestamp{}, fmt.Errorf("watercrawl: invalid timestamp %q", value)
+}
+
+// MarshalJSON implements json.Marshaler. A zero Timestamp is encoded as null.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.Format(time.RFC3339Nano))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. null and "" decode to the zero Timestamp.
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*t = Timestamp{}
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("watercrawl: timestamp must be a string: %w", err)
+	}
+	if value == "" {
+		*t = Timestamp{}
+		return nil
+	}
+
+	parsed, err := ParseTimestamp(value)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
@@ -0,0 +1,64 @@
+package watercrawl
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimestamp_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "RFC 3339", input: `"2025-02-28T10:11:12Z"`, want: time.Date(2025, 2, 28, 10, 11, 12, 0, time.UTC)},
+		{name: "fractional seconds with offset", input: `"2025-02-28T10:11:12.123456+02:00"`, want: time.Date(2025, 2, 28, 8, 11, 12, 123456000, time.UTC)},
+		{name: "offset without colon", input: `"2025-02-28T10:11:12+0000"`, want: time.Date(2025, 2, 28, 10, 11, 12, 0, time.UTC)},
+		{name: "space separator", input: `"2025-02-28 10:11:12.5Z"`, want: time.Date(2025, 2, 28, 10, 11, 12, 500000000, time.UTC)},
+		{name: "without zone", input: `"2025-02-28T10:11:12.000001"`, want: time.Date(2025, 2, 28, 10, 11, 12, 1000, time.UTC)},
+		{name: "empty string", input: `""`},
+		{name: "null", input: `null`},
+		{name: "invalid", input: `"yesterday"`, wantErr: true},
+		{name: "number", input: `1700000000`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ts Timestamp
+			err := json.Unmarshal([]byte(tt.input), &ts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("json.Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !ts.Equal(tt.want) {
+				t.Errorf("json.Unmarshal() = %v, want %v", ts.Time, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimestamp_RoundTrip(t *testing.T) {
+	input := `{"uuid":"test-uuid","status":"finished","created_at":"2025-02-28T10:11:12.123456Z","updated_at":""}`
+
+	var request CrawlRequest
+	if err := json.Unmarshal([]byte(input), &request); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !request.UpdatedAt.IsZero() {
+		t.Errorf("UpdatedAt = %v, want zero", request.UpdatedAt)
+	}
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var decoded CrawlRequest
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() of marshaled request error = %v", err)
+	}
+	if !decoded.CreatedAt.Equal(request.CreatedAt.Time) {
+		t.Errorf("CreatedAt after round trip = %v, want %v", decoded.CreatedAt, request.CreatedAt)
+	}
+}
@@ -0,0 +1,104 @@
+package watercrawl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// URLs is one or more URLs to crawl.
+// A single URL is encoded as a JSON string and several as an array;
+// both shapes are accepted when decoding.
+type URLs []string
+
+// URLsFromURL converts parsed URLs into URLs
+func URLsFromURL(urls ...*url.URL) URLs {
+	result := make(URLs, 0, len(urls))
+	for _, u := range urls {
+		result = append(result, u.String())
+	}
+	return result
+}
+
+// Validate checks that there is at least one URL and that every URL is an absolute http or https URL
+func (u URLs) Validate() error {
+	if u == nil {
+		return &ValidationError{
+			Field:   "url",
+			Message: "URL is required",
+		}
+	}
+	if len(u) == 0 {
+		return &ValidationError{
+			Field:   "url",
+			Message: "URL list cannot be empty",
+		}
+	}
+
+	for i, raw := range u {
+		field := "url"
+		if len(u) > 1 {
+			field = fmt.Sprintf("url[%d]", i)
+		}
+
+		if raw == "" {
+			return &ValidationError{
+				Field:   field,
+				Message: "URL cannot be empty",
+			}
+		}
+
+		parsed, err := url.Parse(raw)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return &ValidationError{
+				Field:   field,
+				Message: "URL must be an absolute http or https URL",
+			}
+		}
+	}
+
+	return nil
+}
+
+// String returns the URLs separated by commas
+func (u URLs) String() string {
+	return strings.Join(u, ", ")
+}
+
+// MarshalJSON implements json.Marshaler
+func (u URLs) MarshalJSON() ([]byte, error) {
+	if u == nil {
+		return []byte("null"), nil
+	}
+	if len(u) == 1 {
+		return json.Marshal(u[0])
+	}
+	return json.Marshal([]string(u))
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (u *URLs) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*u = nil
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var single string
+		if err := json.Unmarshal(data, &single); err != nil {
+			return err
+		}
+		*u = URLs{single}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return fmt.Errorf("watercrawl: url must be a string or array of strings: %w", err)
+	}
+	*u = many
+	return nil
+}
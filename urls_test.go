@@ -0,0 +1,70 @@
+package watercrawl
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestURLs_JSON(t *testing.T) {
+	tests := []struct {
+		name string
+		urls URLs
+		json string
+	}{
+		{name: "single", urls: URLs{"https://example.com"}, json: `"https://example.com"`},
+		{name: "many", urls: URLs{"https://example.com", "https://example.org"}, json: `["https://example.com","https://example.org"]`},
+		{name: "nil", urls: nil, json: `null`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.urls)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(data) != tt.json {
+				t.Errorf("json.Marshal() = %s, want %s", data, tt.json)
+			}
+
+			var decoded URLs
+			if err := json.Unmarshal([]byte(tt.json), &decoded); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(decoded, tt.urls) {
+				t.Errorf("json.Unmarshal() = %#v, want %#v", decoded, tt.urls)
+			}
+		})
+	}
+
+	var invalid URLs
+	if err := json.Unmarshal([]byte(`123`), &invalid); err == nil {
+		t.Error("json.Unmarshal(123) error = nil, want error")
+	}
+}
+
+func TestCrawlRequest_URLShapes(t *testing.T) {
+	var request CrawlRequest
+	if err := json.Unmarshal([]byte(`{"uuid":"a","url":["https://example.com","https://example.org"]}`), &request); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if len(request.URL) != 2 || request.URL[1] != "https://example.org" {
+		t.Errorf("URL = %v, want two URLs", request.URL)
+	}
+}
+
+func TestURLsFromURL(t *testing.T) {
+	u, err := url.Parse("https://example.com/path?q=1")
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+
+	urls := URLsFromURL(u)
+	if err := urls.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if urls[0] != "https://example.com/path?q=1" {
+		t.Errorf("URLsFromURL() = %v, want https://example.com/path?q=1", urls)
+	}
+}
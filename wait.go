@@ -0,0 +1,126 @@
+package watercrawl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultWaitInterval is the polling interval used by WaitForCrawl when none is set
+const DefaultWaitInterval = 2 * time.Second
+
+// WaitOptions configures WaitForCrawl
+type WaitOptions struct {
+	// Interval is the delay between polls, DefaultWaitInterval if 0
+	Interval time.Duration
+
+	// Multiplier grows the interval after each poll that saw no change.
+	// Values below 1 keep the interval fixed.
+	Multiplier float64
+
+	// MaxInterval caps the interval grown by Multiplier, 0 for no cap
+	MaxInterval time.Duration
+
+	// Timeout bounds the whole wait, in addition to any deadline of ctx.
+	// 0 means no timeout.
+	Timeout time.Duration
+
+	// OnProgress, if set, is called with the progress and status of the
+	// crawl request on the first poll and whenever either changes
+	OnProgress func(progress float64, status CrawlStatus)
+}
+
+// WaitForCrawl polls a crawl request until it reaches a terminal status and
+// returns it. It works without the event stream, e.g. behind proxies that
+// strip Server-Sent Events. A *TimeoutError is returned when opts.Timeout or
+// the deadline of ctx passes first. opts may be nil.
+//
+// The final crawl request is returned whatever the terminal status; use
+// CrawlStatus.IsSuccess to tell a successful crawl from a failed one.
+func (c *Client) WaitForCrawl(ctx context.Context, id string, opts *WaitOptions) (*CrawlRequest, error) {
+	if opts == nil {
+		opts = &WaitOptions{}
+	}
+
+	base := opts.Interval
+	if base <= 0 {
+		base = DefaultWaitInterval
+	}
+	interval := base
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	var last *CrawlRequest
+
+	for {
+		request, err := c.GetCrawlRequest(ctx, id)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return nil, c.waitError(ctx, id, last, start)
+		case err != nil && isPermanentError(err):
+			return nil, err
+		case err != nil:
+			c.logger.Warnf("Polling crawl request %s failed: %v", id, err)
+		default:
+			changed := last == nil || last.Status != request.Status || last.Progress != request.Progress
+			last = request
+
+			if changed && opts.OnProgress != nil {
+				opts.OnProgress(request.Progress, request.Status)
+			}
+			if request.Status.IsTerminal() {
+				return request, nil
+			}
+			if changed {
+				// Back off only while nothing happens
+				interval = base
+			}
+		}
+
+		c.logger.Debugf("Crawl request %s not finished, polling again in %s", id, interval)
+		if err := sleepContext(ctx, interval); err != nil {
+			return nil, c.waitError(ctx, id, last, start)
+		}
+
+		interval = nextWaitInterval(interval, opts)
+	}
+}
+
+// waitError returns the error for a wait interrupted by ctx
+func (c *Client) waitError(ctx context.Context, id string, last *CrawlRequest, start time.Time) error {
+	err := ctx.Err()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	status := "unknown"
+	if last != nil {
+		status = last.Status.String()
+	}
+
+	return &TimeoutError{
+		Operation: "WaitForCrawl",
+		Message: fmt.Sprintf("crawl request %s still %s after %s",
+			id, status, time.Since(start).Round(time.Millisecond)),
+		Err: err,
+	}
+}
+
+// nextWaitInterval grows interval according to opts
+func nextWaitInterval(interval time.Duration, opts *WaitOptions) time.Duration {
+	if opts.Multiplier <= 1 {
+		return interval
+	}
+
+	next := time.Duration(float64(interval) * opts.Multiplier)
+	if opts.MaxInterval > 0 && next > opts.MaxInterval {
+		next = opts.MaxInterval
+	}
+	return next
+}
@@ -0,0 +1,185 @@
+package watercrawl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newStatusServer serves the crawl request with the given statuses, one per poll,
+// repeating the last one
+func newStatusServer(t *testing.T, statuses ...CrawlStatus) (*httptest.Server, *int32) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/core/crawl-requests/test-uuid/" {
+			t.Errorf("Expected path /api/v1/core/crawl-requests/test-uuid/, got %s", r.URL.Path)
+		}
+
+		i := int(atomic.AddInt32(&polls, 1)) - 1
+		if i >= len(statuses) {
+			i = len(statuses) - 1
+		}
+		request := CrawlRequest{UUID: "test-uuid", Status: statuses[i], Progress: float64(i * 10)}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(request); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+	return server, &polls
+}
+
+func TestClient_WaitForCrawl(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []CrawlStatus
+		want     CrawlStatus
+		wantCall []CrawlStatus
+	}{
+		{
+			name:     "finished",
+			statuses: []CrawlStatus{CrawlStatusNew, CrawlStatusRunning, CrawlStatusFinished},
+			want:     CrawlStatusFinished,
+			wantCall: []CrawlStatus{CrawlStatusNew, CrawlStatusRunning, CrawlStatusFinished},
+		},
+		{
+			name:     "already terminal",
+			statuses: []CrawlStatus{CrawlStatusFailed},
+			want:     CrawlStatusFailed,
+			wantCall: []CrawlStatus{CrawlStatusFailed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, polls := newStatusServer(t, tt.statuses...)
+			defer server.Close()
+
+			client := NewClient("test-key", server.URL+"/")
+
+			var calls []CrawlStatus
+			got, err := client.WaitForCrawl(context.Background(), "test-uuid", &WaitOptions{
+				Interval: time.Millisecond,
+				OnProgress: func(progress float64, status CrawlStatus) {
+					calls = append(calls, status)
+				},
+			})
+			if err != nil {
+				t.Fatalf("WaitForCrawl() error = %v", err)
+			}
+			if got.Status != tt.want {
+				t.Errorf("WaitForCrawl().Status = %v, want %v", got.Status, tt.want)
+			}
+			if !reflect.DeepEqual(calls, tt.wantCall) {
+				t.Errorf("OnProgress statuses = %v, want %v", calls, tt.wantCall)
+			}
+			if got := int(atomic.LoadInt32(polls)); got != len(tt.statuses) {
+				t.Errorf("polls = %d, want %d", got, len(tt.statuses))
+			}
+		})
+	}
+}
+
+func TestClient_WaitForCrawl_Timeout(t *testing.T) {
+	server, _ := newStatusServer(t, CrawlStatusRunning)
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	tests := []struct {
+		name string
+		ctx  func() (context.Context, context.CancelFunc)
+		opts *WaitOptions
+	}{
+		{
+			name: "timeout option",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			opts: &WaitOptions{Interval: time.Millisecond, Timeout: 20 * time.Millisecond},
+		},
+		{
+			name: "context deadline",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 20*time.Millisecond)
+			},
+			opts: &WaitOptions{Interval: time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			_, err := client.WaitForCrawl(ctx, "test-uuid", tt.opts)
+
+			var timeoutErr *TimeoutError
+			if !errors.As(err, &timeoutErr) {
+				t.Fatalf("WaitForCrawl() error = %v, want *TimeoutError", err)
+			}
+			if timeoutErr.Operation != "WaitForCrawl" {
+				t.Errorf("TimeoutError.Operation = %v, want %v", timeoutErr.Operation, "WaitForCrawl")
+			}
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("errors.Is(err, context.DeadlineExceeded) = false, want true")
+			}
+		})
+	}
+}
+
+func TestClient_WaitForCrawl_Canceled(t *testing.T) {
+	server, _ := newStatusServer(t, CrawlStatusRunning)
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	_, err := client.WaitForCrawl(ctx, "test-uuid", &WaitOptions{Interval: time.Millisecond})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("WaitForCrawl() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestClient_WaitForCrawl_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key", server.URL+"/")
+
+	_, err := client.WaitForCrawl(context.Background(), "test-uuid", &WaitOptions{Interval: time.Millisecond})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("WaitForCrawl() error = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestNextWaitInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		opts     WaitOptions
+		want     time.Duration
+	}{
+		{"fixed", time.Second, WaitOptions{}, time.Second},
+		{"multiplier", time.Second, WaitOptions{Multiplier: 2}, 2 * time.Second},
+		{"capped", time.Second, WaitOptions{Multiplier: 2, MaxInterval: 1500 * time.Millisecond}, 1500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextWaitInterval(tt.interval, &tt.opts); got != tt.want {
+				t.Errorf("nextWaitInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
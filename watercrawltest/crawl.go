@@ -0,0 +1,288 @@
+package watercrawltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/watercrawl/watercrawl-go"
+)
+
+// Crawl describes how a crawl request runs: once created it is running,
+// then produces its results one step at a time, sending a result and a
+// progress event for each, and finally ends with Status
+type Crawl struct {
+	// Results are produced in order. UUID, Status and timestamps are filled
+	// in when empty.
+	Results []watercrawl.CrawlResult
+
+	// Error, if set, is sent as an error event after the results
+	Error string
+
+	// Status is the final status, watercrawl.CrawlStatusFinished if empty.
+	// A non-terminal status leaves the crawl running until it is stopped.
+	Status watercrawl.CrawlStatus
+}
+
+// DefaultCrawl produces one result per URL of the request, with the URL as
+// Markdown content, and finishes
+func DefaultCrawl(request watercrawl.CrawlRequest) Crawl {
+	var crawl Crawl
+	for _, u := range request.URL {
+		result, _ := json.Marshal(map[string]interface{}{
+			"markdown": "# " + u,
+			"metadata": map[string]string{"title": u},
+		})
+		crawl.Results = append(crawl.Results, watercrawl.CrawlResult{
+			URL:    u,
+			Title:  u,
+			Result: result,
+		})
+	}
+	return crawl
+}
+
+// crawlRequest is the state of a crawl request held by the server
+type crawlRequest struct {
+	request watercrawl.CrawlRequest
+	// results are the results produced so far
+	results []watercrawl.CrawlResult
+	// events is the log of the status event stream, replayed to each stream
+	events [][]byte
+	// changed is closed and replaced whenever the request changes
+	changed chan struct{}
+	// stopped is closed when the request is canceled
+	stopped chan struct{}
+}
+
+func newCrawlRequest(request watercrawl.CrawlRequest) *crawlRequest {
+	return &crawlRequest{
+		request: request,
+		changed: make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+// AddCrawlRequest stores a crawl request as is, with its results, without
+// running it. A UUID is assigned if request.UUID is empty. It returns the
+// stored request.
+func (s *Server) AddCrawlRequest(request watercrawl.CrawlRequest, results ...watercrawl.CrawlResult) watercrawl.CrawlRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	if request.UUID == "" {
+		request.UUID = fmt.Sprintf("crawl-%d", s.nextID)
+	}
+	if request.Status == "" {
+		request.Status = watercrawl.CrawlStatusFinished
+	}
+
+	cr := newCrawlRequest(request)
+	cr.results = append(cr.results, results...)
+	if request.Status.IsTerminal() {
+		s.emit(cr, watercrawl.EventTypeState, request)
+	}
+	s.add(cr)
+	return request
+}
+
+// CrawlRequest returns the current state of a crawl request
+func (s *Server) CrawlRequest(id string) (watercrawl.CrawlRequest, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cr, ok := s.requests[id]
+	if !ok {
+		return watercrawl.CrawlRequest{}, false
+	}
+	return cr.request, true
+}
+
+// add stores a crawl request. s.mu must be held.
+func (s *Server) add(cr *crawlRequest) {
+	s.order = append(s.order, cr)
+	s.requests[cr.request.UUID] = cr
+}
+
+// start runs crawl for cr in the background
+func (s *Server) start(cr *crawlRequest, crawl Crawl) {
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		s.run(cr, crawl)
+	}()
+}
+
+// run moves cr through the steps of crawl, giving up when it is stopped or
+// the server is closed
+func (s *Server) run(cr *crawlRequest, crawl Crawl) {
+	// step applies fn unless the request ended meanwhile
+	step := func(fn func()) bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if cr.request.Status.IsTerminal() {
+			return false
+		}
+		fn()
+		return true
+	}
+
+	running := step(func() {
+		s.setStatus(cr, watercrawl.CrawlStatusRunning)
+	})
+	if !running {
+		return
+	}
+
+	for i, result := range crawl.Results {
+		if !s.wait(cr) {
+			return
+		}
+		i, result := i, result
+		ok := step(func() {
+			now := watercrawl.Timestamp{Time: time.Now()}
+			if result.UUID == "" {
+				result.UUID = fmt.Sprintf("%s-result-%d", cr.request.UUID, i+1)
+			}
+			if result.Status == "" {
+				result.Status = "success"
+			}
+			if result.CreatedAt.IsZero() {
+				result.CreatedAt = now
+			}
+			if result.UpdatedAt.IsZero() {
+				result.UpdatedAt = now
+			}
+			cr.results = append(cr.results, result)
+			cr.request.Progress = float64(i+1) / float64(len(crawl.Results)) * 100
+			cr.request.UpdatedAt = now
+
+			s.emit(cr, watercrawl.EventTypeResult, result)
+			s.emit(cr, watercrawl.EventTypeProgress, watercrawl.ProgressEvent{
+				Progress: cr.request.Progress,
+				Status:   cr.request.Status,
+			})
+		})
+		if !ok {
+			return
+		}
+	}
+
+	status := crawl.Status
+	if status == "" {
+		status = watercrawl.CrawlStatusFinished
+	}
+	step(func() {
+		if crawl.Error != "" {
+			s.emit(cr, watercrawl.EventTypeError, watercrawl.ErrorEvent{Message: crawl.Error})
+		}
+		if status.IsSuccess() {
+			cr.request.Progress = 100
+		}
+		s.setStatus(cr, status)
+	})
+}
+
+// wait waits for the interval between two steps, returning false if the
+// request is stopped or the server closed first
+func (s *Server) wait(cr *crawlRequest) bool {
+	if s.eventInterval <= 0 {
+		select {
+		case <-cr.stopped:
+			return false
+		case <-s.done:
+			return false
+		default:
+			return true
+		}
+	}
+
+	timer := time.NewTimer(s.eventInterval)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-cr.stopped:
+		return false
+	case <-s.done:
+		return false
+	}
+}
+
+// cancel stops cr unless it already ended. s.mu must be held.
+func (s *Server) cancel(cr *crawlRequest) {
+	if cr.request.Status.IsTerminal() {
+		return
+	}
+	s.setStatus(cr, watercrawl.CrawlStatusCanceled)
+	close(cr.stopped)
+}
+
+// setStatus changes the status of cr and sends a state event. s.mu must be held.
+func (s *Server) setStatus(cr *crawlRequest, status watercrawl.CrawlStatus) {
+	cr.request.Status = status
+	cr.request.UpdatedAt = watercrawl.Timestamp{Time: time.Now()}
+	s.emit(cr, watercrawl.EventTypeState, cr.request)
+}
+
+// emit appends an event to the stream of cr. s.mu must be held.
+func (s *Server) emit(cr *crawlRequest, eventType watercrawl.EventType, data interface{}) {
+	message, err := json.Marshal(map[string]interface{}{"type": eventType, "data": data})
+	if err != nil {
+		panic(fmt.Sprintf("watercrawltest: encoding %s event: %v", eventType, err))
+	}
+	cr.events = append(cr.events, message)
+
+	close(cr.changed)
+	cr.changed = make(chan struct{})
+}
+
+// handleStatus streams the events of cr, starting after the Last-Event-ID
+// sent by the client, until the request ends
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request, cr *crawlRequest) {
+	next := 0
+	if id, err := strconv.Atoi(r.Header.Get("Last-Event-ID")); err == nil && id > 0 {
+		next = id
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+
+	for {
+		s.mu.Lock()
+		var events [][]byte
+		if next < len(cr.events) {
+			events = cr.events[next:]
+		}
+		ended := cr.request.Status.IsTerminal()
+		changed := cr.changed
+		s.mu.Unlock()
+
+		for _, event := range events {
+			next++
+			if _, err := fmt.Fprintf(w, "id: %d\ndata: %s\n\n", next, event); err != nil {
+				return
+			}
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if ended {
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		case <-s.done:
+			return
+		}
+	}
+}
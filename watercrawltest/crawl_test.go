@@ -0,0 +1,176 @@
+package watercrawltest
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/watercrawl/watercrawl-go"
+)
+
+func TestServer_StatusEvents(t *testing.T) {
+	server := NewServer(WithEventInterval(5 * time.Millisecond))
+	defer server.Close()
+
+	client := server.NewClient()
+	ctx := context.Background()
+
+	request, err := client.CreateCrawlRequest(ctx, watercrawl.CreateCrawlRequestInput{
+		URL: watercrawl.URLs{"https://example.com/a", "https://example.com/b"},
+	})
+	if err != nil {
+		t.Fatalf("CreateCrawlRequest() error = %v", err)
+	}
+
+	events, err := client.MonitorCrawlRequest(ctx, request.UUID, false)
+	if err != nil {
+		t.Fatalf("MonitorCrawlRequest() error = %v", err)
+	}
+	var types []string
+	var lastProgress float64
+	for event := range events {
+		types = append(types, string(event.Type))
+		if typed, err := event.Event(); err == nil {
+			if progress, ok := typed.(watercrawl.ProgressEvent); ok {
+				lastProgress = progress.Progress
+			}
+		}
+	}
+
+	want := "state result progress result progress state"
+	if got := strings.Join(types, " "); got != want {
+		t.Errorf("event types = %q, want %q", got, want)
+	}
+	if lastProgress != 100 {
+		t.Errorf("last progress = %v, want 100", lastProgress)
+	}
+
+	got, _ := server.CrawlRequest(request.UUID)
+	if got.Status != watercrawl.CrawlStatusFinished {
+		t.Errorf("status = %s, want %s", got.Status, watercrawl.CrawlStatusFinished)
+	}
+
+	results, err := client.GetCrawlRequestResults(ctx, request.UUID, 1, 1)
+	if err != nil {
+		t.Fatalf("GetCrawlRequestResults() error = %v", err)
+	}
+	if results.Count != 2 || len(results.Results) != 1 || results.Next == nil {
+		t.Errorf("GetCrawlRequestResults(1, 1) = %d of %d, next %v, want 1 of 2 with a next page", len(results.Results), results.Count, results.Next)
+	}
+}
+
+func TestServer_LastEventID(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	request := server.AddCrawlRequest(watercrawl.CrawlRequest{Status: watercrawl.CrawlStatusRunning})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		server.NewClient().StopCrawlRequest(context.Background(), request.UUID)
+	}()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/v1/core/crawl-requests/"+request.UUID+"/status/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Last-Event-ID", "0")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET status error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) != 2 || lines[0] != "id: 1" || !strings.Contains(lines[1], `"status":"canceled"`) {
+		t.Fatalf("stream = %q, want the canceled state event with id 1", lines)
+	}
+
+	// Resuming after the last event replays nothing and ends at once
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET status error = %v", err)
+	}
+	defer resp.Body.Close()
+	if scanner := bufio.NewScanner(resp.Body); scanner.Scan() {
+		t.Errorf("resumed stream = %q, want no events", scanner.Text())
+	}
+}
+
+func TestServer_Stop(t *testing.T) {
+	server := NewServer(WithEventInterval(time.Hour))
+	defer server.Close()
+
+	client := server.NewClient()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Scrape(ctx, "https://example.com", nil)
+	var abandoned *watercrawl.AbandonedCrawlError
+	if !errors.As(err, &abandoned) || !abandoned.Stopped {
+		t.Fatalf("Scrape() error = %v, want a stopped *AbandonedCrawlError", err)
+	}
+
+	request, ok := server.CrawlRequest(abandoned.CrawlRequest)
+	if !ok || request.Status != watercrawl.CrawlStatusCanceled {
+		t.Errorf("CrawlRequest(%s) = %+v, want status %s", abandoned.CrawlRequest, request, watercrawl.CrawlStatusCanceled)
+	}
+	if n := len(server.RequestsMatching(http.MethodDelete, "core/crawl-requests/*/")); n != 1 {
+		t.Errorf("stop requests = %d, want 1", n)
+	}
+}
+
+func TestServer_CrawlFailure(t *testing.T) {
+	server := NewServer(WithCrawl(func(request watercrawl.CrawlRequest) Crawl {
+		return Crawl{Error: "blocked by robots.txt", Status: watercrawl.CrawlStatusFailed}
+	}))
+	defer server.Close()
+
+	_, err := server.NewClient().Scrape(context.Background(), "https://example.com", nil)
+	if err == nil || !strings.Contains(err.Error(), "failed") {
+		t.Errorf("Scrape() error = %v, want crawl failure", err)
+	}
+}
+
+func TestServer_Download(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	request := server.AddCrawlRequest(watercrawl.CrawlRequest{},
+		watercrawl.CrawlResult{UUID: "r1", URL: "https://example.com/a"},
+		watercrawl.CrawlResult{UUID: "r2", URL: "https://example.com/b"},
+	)
+
+	client := server.NewClient()
+	ctx := context.Background()
+
+	results, err := client.DownloadCrawlResults(ctx, request.UUID)
+	if err != nil {
+		t.Fatalf("DownloadCrawlResults() error = %v", err)
+	}
+	if len(results) != 2 || results[0].UUID != "r1" || results[1].UUID != "r2" {
+		t.Errorf("DownloadCrawlResults() = %+v, want r1 and r2", results)
+	}
+
+	path := filepath.Join(t.TempDir(), "results.json")
+	n, err := client.DownloadCrawlRequestToFile(ctx, request.UUID, path)
+	if err != nil {
+		t.Fatalf("DownloadCrawlRequestToFile() error = %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() != n {
+		t.Errorf("downloaded file = %v, %v, want %d bytes", info, err, n)
+	}
+}
@@ -0,0 +1,521 @@
+// Package watercrawltest provides an in-memory fake of the WaterCrawl API
+// for testing code built on the watercrawl package.
+//
+//	server := watercrawltest.NewServer()
+//	defer server.Close()
+//
+//	client := server.NewClient()
+//	result, err := client.Scrape(ctx, "https://example.com", nil)
+//
+// The server implements the crawl request endpoints: create, list and get
+// with pagination, stop, the status event stream, results and download.
+// Crawl requests run in the background as described by a Crawl, failures
+// and latency can be scripted with faults, and every request received is
+// recorded for assertions.
+package watercrawltest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/watercrawl/watercrawl-go"
+)
+
+// DefaultPageSize is the page size of list endpoints when none is requested
+const DefaultPageSize = 10
+
+// DefaultAPIKey is the API key of clients created by Server.NewClient when
+// the server does not require a specific one
+const DefaultAPIKey = "test-key"
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithAPIKey makes the server reject requests without this X-API-Key with 401
+func WithAPIKey(apiKey string) Option {
+	return func(s *Server) {
+		s.apiKey = apiKey
+	}
+}
+
+// WithLatency delays every response by d
+func WithLatency(d time.Duration) Option {
+	return func(s *Server) {
+		s.latency = d
+	}
+}
+
+// WithEventInterval sets the delay between the steps of a crawl, each step
+// producing one result. The default of 0 runs crawls to completion at once.
+func WithEventInterval(d time.Duration) Option {
+	return func(s *Server) {
+		s.eventInterval = d
+	}
+}
+
+// WithCrawl sets how crawl requests run. fn is called with each created
+// crawl request; the default produces one result per URL and finishes.
+func WithCrawl(fn func(request watercrawl.CrawlRequest) Crawl) Option {
+	return func(s *Server) {
+		s.crawl = fn
+	}
+}
+
+// Request is a request received by the server
+type Request struct {
+	Method string
+	// Path is the request path, e.g. /api/v1/core/crawl-requests/
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Fault scripts the response to the requests matching Method and Path
+type Fault struct {
+	// Method matches any method if empty
+	Method string
+	// Path is a path.Match pattern relative to the API root, such as
+	// "core/crawl-requests/*/status/". It matches any path if empty.
+	Path string
+
+	// Delay delays the response to matching requests
+	Delay time.Duration
+
+	// Status is the status code of the response. If 0, matching requests
+	// are served normally once delayed.
+	Status int
+	// Body is the response body, a JSON detail message if empty
+	Body string
+	// Header is added to the response, e.g. Retry-After
+	Header http.Header
+
+	// Abort closes the connection without a response, like a network failure
+	Abort bool
+
+	// Times is the number of requests the fault applies to, 0 for all
+	Times int
+}
+
+// Server is an in-memory fake of the WaterCrawl API. Its state is safe for
+// concurrent use by the handlers and the test.
+type Server struct {
+	*httptest.Server
+
+	apiKey        string
+	latency       time.Duration
+	eventInterval time.Duration
+	crawl         func(request watercrawl.CrawlRequest) Crawl
+	prefix        string
+
+	// done is closed when the server is closed, ending event streams and crawls
+	done      chan struct{}
+	closeOnce sync.Once
+	running   sync.WaitGroup
+
+	mu          sync.Mutex
+	nextID      int
+	order       []*crawlRequest
+	requests    map[string]*crawlRequest
+	idempotency map[string]*crawlRequest
+	faults      []*fault
+	received    []Request
+}
+
+// fault is a scripted fault and the number of requests it still applies to
+type fault struct {
+	Fault
+	remaining int
+}
+
+// NewServer starts a fake WaterCrawl API server. The caller must Close it.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		crawl:       DefaultCrawl,
+		prefix:      "/api/" + watercrawl.DefaultAPIVersion + "/",
+		done:        make(chan struct{}),
+		requests:    map[string]*crawlRequest{},
+		idempotency: map[string]*crawlRequest{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Close ends event streams and crawls, then shuts the server down
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+	s.Server.Close()
+	s.running.Wait()
+}
+
+// NewClient returns a watercrawl client for the server, using its API key
+func (s *Server) NewClient(opts ...watercrawl.Option) *watercrawl.Client {
+	apiKey := s.apiKey
+	if apiKey == "" {
+		apiKey = DefaultAPIKey
+	}
+	return watercrawl.NewClient(apiKey, s.URL+"/", opts...)
+}
+
+// AddFault scripts a fault. Faults are matched in the order they were added.
+func (s *Server) AddFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault{Fault: f, remaining: f.Times})
+}
+
+// Requests returns the requests received so far, in order
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.received...)
+}
+
+// RequestsMatching returns the requests received so far that match method
+// and pattern, with the same rules as Fault
+func (s *Server) RequestsMatching(method, pattern string) []Request {
+	var matching []Request
+	for _, r := range s.Requests() {
+		if s.matches(method, pattern, r.Method, r.Path) {
+			matching = append(matching, r)
+		}
+	}
+	return matching
+}
+
+// matches reports whether a request matches a method and path pattern
+func (s *Server) matches(method, pattern, requestMethod, requestPath string) bool {
+	if method != "" && method != requestMethod {
+		return false
+	}
+	if pattern == "" {
+		return true
+	}
+	matched, err := path.Match(pattern, strings.TrimPrefix(requestPath, s.prefix))
+	return err == nil && matched
+}
+
+// serveHTTP records the request, applies latency and faults and routes it
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeDetail(w, http.StatusBadRequest, "Failed to read request body.")
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	s.mu.Lock()
+	s.received = append(s.received, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	f := s.takeFault(r)
+	s.mu.Unlock()
+
+	delay := s.latency
+	if f != nil {
+		delay += f.Delay
+	}
+	if delay > 0 && !s.sleep(r, delay) {
+		return
+	}
+
+	if f != nil {
+		if f.Abort {
+			panic(http.ErrAbortHandler)
+		}
+		if f.Status != 0 {
+			for key, values := range f.Header {
+				for _, value := range values {
+					w.Header().Add(key, value)
+				}
+			}
+			if f.Body == "" {
+				writeDetail(w, f.Status, http.StatusText(f.Status))
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(f.Status)
+			io.WriteString(w, f.Body)
+			return
+		}
+	}
+
+	if s.apiKey != "" && r.Header.Get("X-API-Key") != s.apiKey {
+		writeDetail(w, http.StatusUnauthorized, "Invalid API key.")
+		return
+	}
+
+	s.route(w, r)
+}
+
+// takeFault returns the first fault matching r, consuming one of its times
+func (s *Server) takeFault(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if !s.matches(f.Method, f.Path, r.Method, r.URL.Path) {
+			continue
+		}
+		if f.Times > 0 {
+			f.remaining--
+			if f.remaining == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return &f.Fault
+	}
+	return nil
+}
+
+// sleep waits for d, returning false if the request or server ends first
+func (s *Server) sleep(r *http.Request, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-r.Context().Done():
+		return false
+	case <-s.done:
+		return false
+	}
+}
+
+// route dispatches a request to the handler of its endpoint
+func (s *Server) route(w http.ResponseWriter, r *http.Request) {
+	rel := strings.TrimPrefix(r.URL.Path, s.prefix)
+	if rel == r.URL.Path || !strings.HasPrefix(rel, "core/crawl-requests/") {
+		writeDetail(w, http.StatusNotFound, "Not found.")
+		return
+	}
+
+	rest := strings.TrimSuffix(strings.TrimPrefix(rel, "core/crawl-requests/"), "/")
+	if rest == "" {
+		switch r.Method {
+		case http.MethodGet:
+			s.handleList(w, r)
+		case http.MethodPost:
+			s.handleCreate(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+		return
+	}
+
+	parts := strings.Split(rest, "/")
+	if len(parts) > 2 {
+		writeDetail(w, http.StatusNotFound, "Not found.")
+		return
+	}
+
+	s.mu.Lock()
+	cr := s.requests[parts[0]]
+	s.mu.Unlock()
+	if cr == nil {
+		writeDetail(w, http.StatusNotFound, "Not found.")
+		return
+	}
+
+	action := ""
+	if len(parts) == 2 {
+		action = parts[1]
+	}
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		s.handleGet(w, cr)
+	case action == "" && r.Method == http.MethodDelete:
+		s.handleStop(w, cr)
+	case action == "status" && r.Method == http.MethodGet:
+		s.handleStatus(w, r, cr)
+	case action == "results" && r.Method == http.MethodGet:
+		s.handleResults(w, r, cr)
+	case action == "download" && r.Method == http.MethodGet:
+		s.handleDownload(w, r, cr)
+	case action == "" || action == "status" || action == "results" || action == "download":
+		writeMethodNotAllowed(w, r)
+	default:
+		writeDetail(w, http.StatusNotFound, "Not found.")
+	}
+}
+
+// handleList lists crawl requests, most recent first
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	requests := make([]watercrawl.CrawlRequest, 0, len(s.order))
+	for i := len(s.order) - 1; i >= 0; i-- {
+		requests = append(requests, s.order[i].request)
+	}
+	s.mu.Unlock()
+
+	writePage(w, r, requests)
+}
+
+// handleCreate creates a crawl request and starts running it
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var input watercrawl.CreateCrawlRequestInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeDetail(w, http.StatusBadRequest, fmt.Sprintf("JSON parse error - %v", err))
+		return
+	}
+	if len(input.URL) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string][]string{"url": {"This field is required."}})
+		return
+	}
+
+	key := r.Header.Get(watercrawl.IdempotencyKeyHeader)
+
+	s.mu.Lock()
+	if cr := s.idempotency[key]; key != "" && cr != nil {
+		request := cr.request
+		s.mu.Unlock()
+		writeJSON(w, http.StatusCreated, request)
+		return
+	}
+
+	now := watercrawl.Timestamp{Time: time.Now()}
+	s.nextID++
+	cr := newCrawlRequest(watercrawl.CrawlRequest{
+		UUID:      fmt.Sprintf("crawl-%d", s.nextID),
+		URL:       input.URL,
+		Status:    watercrawl.CrawlStatusNew,
+		Options:   input.Options,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	s.add(cr)
+	if key != "" {
+		s.idempotency[key] = cr
+	}
+	request := cr.request
+	s.mu.Unlock()
+
+	s.start(cr, s.crawl(request))
+	writeJSON(w, http.StatusCreated, request)
+}
+
+// handleGet returns a crawl request
+func (s *Server) handleGet(w http.ResponseWriter, cr *crawlRequest) {
+	s.mu.Lock()
+	request := cr.request
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, request)
+}
+
+// handleStop cancels a crawl request unless it already ended
+func (s *Server) handleStop(w http.ResponseWriter, cr *crawlRequest) {
+	s.mu.Lock()
+	s.cancel(cr)
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleResults lists the results produced so far
+func (s *Server) handleResults(w http.ResponseWriter, r *http.Request, cr *crawlRequest) {
+	s.mu.Lock()
+	results := append([]watercrawl.CrawlResult{}, cr.results...)
+	s.mu.Unlock()
+
+	writePage(w, r, results)
+}
+
+// handleDownload returns the results produced so far as a JSON array,
+// honouring Range requests
+func (s *Server) handleDownload(w http.ResponseWriter, r *http.Request, cr *crawlRequest) {
+	s.mu.Lock()
+	results := append([]watercrawl.CrawlResult{}, cr.results...)
+	s.mu.Unlock()
+
+	body, err := json.Marshal(results)
+	if err != nil {
+		writeDetail(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(body))
+}
+
+// writePage writes the page of items requested by the page and page_size
+// query parameters, with links to the neighbouring pages
+func writePage[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	query := r.URL.Query()
+	pageSize, err := strconv.Atoi(query.Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	page := 1
+	if raw := query.Get("page"); raw != "" {
+		page, err = strconv.Atoi(raw)
+		if err != nil || page < 1 || (page-1)*pageSize >= len(items) && page > 1 {
+			writeDetail(w, http.StatusNotFound, "Invalid page.")
+			return
+		}
+	}
+
+	start := (page - 1) * pageSize
+	end := start + pageSize
+	if end > len(items) {
+		end = len(items)
+	}
+
+	link := func(page int) *string {
+		q := r.URL.Query()
+		q.Set("page", strconv.Itoa(page))
+		q.Set("page_size", strconv.Itoa(pageSize))
+		u := "http://" + r.Host + r.URL.Path + "?" + q.Encode()
+		return &u
+	}
+
+	list := struct {
+		Count    int     `json:"count"`
+		Next     *string `json:"next"`
+		Previous *string `json:"previous"`
+		Results  []T     `json:"results"`
+	}{Count: len(items), Results: items[start:end]}
+	if end < len(items) {
+		list.Next = link(page + 1)
+	}
+	if page > 1 {
+		list.Previous = link(page - 1)
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeDetail writes an error response in the API's {"detail": ...} format
+func writeDetail(w http.ResponseWriter, status int, detail string) {
+	writeJSON(w, status, map[string]string{"detail": detail})
+}
+
+// writeMethodNotAllowed rejects a method the endpoint does not support
+func writeMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeDetail(w, http.StatusMethodNotAllowed, fmt.Sprintf("Method %q not allowed.", r.Method))
+}
@@ -0,0 +1,188 @@
+package watercrawltest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/watercrawl/watercrawl-go"
+)
+
+func TestServer_Scrape(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client := server.NewClient()
+
+	result, err := client.Scrape(context.Background(), "https://example.com/page", nil)
+	if err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	if result.Result == nil || result.Result.URL != "https://example.com/page" {
+		t.Fatalf("Scrape() result = %+v, want result for https://example.com/page", result.Result)
+	}
+	data, err := result.Result.ResultData()
+	if err != nil || data.Markdown != "# https://example.com/page" {
+		t.Errorf("ResultData() = %+v, %v, want Markdown of the URL", data, err)
+	}
+
+	created := server.RequestsMatching(http.MethodPost, "core/crawl-requests/")
+	if len(created) != 1 {
+		t.Fatalf("create requests = %d, want 1", len(created))
+	}
+	if got := created[0].Header.Get("X-API-Key"); got != DefaultAPIKey {
+		t.Errorf("X-API-Key = %q, want %q", got, DefaultAPIKey)
+	}
+	if len(server.RequestsMatching(http.MethodGet, "core/crawl-requests/*/status/")) != 1 {
+		t.Errorf("requests = %+v, want one status stream", server.Requests())
+	}
+}
+
+func TestServer_Pagination(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	for i := 0; i < 25; i++ {
+		server.AddCrawlRequest(watercrawl.CrawlRequest{URL: watercrawl.URLs{"https://example.com"}})
+	}
+
+	client := server.NewClient()
+	ctx := context.Background()
+
+	tests := []struct {
+		page, pageSize int
+		wantResults    int
+		wantNext       bool
+		wantPrevious   bool
+	}{
+		{page: 1, pageSize: 10, wantResults: 10, wantNext: true},
+		{page: 2, pageSize: 10, wantResults: 10, wantNext: true, wantPrevious: true},
+		{page: 3, pageSize: 10, wantResults: 5, wantPrevious: true},
+		{page: 1, pageSize: 30, wantResults: 25},
+	}
+	for _, tt := range tests {
+		list, err := client.GetCrawlRequests(ctx, tt.page, tt.pageSize)
+		if err != nil {
+			t.Fatalf("GetCrawlRequests(%d, %d) error = %v", tt.page, tt.pageSize, err)
+		}
+		if list.Count != 25 || len(list.Results) != tt.wantResults {
+			t.Errorf("GetCrawlRequests(%d, %d) = %d of %d, want %d of 25", tt.page, tt.pageSize, len(list.Results), list.Count, tt.wantResults)
+		}
+		if (list.Next != nil) != tt.wantNext || (list.Previous != nil) != tt.wantPrevious {
+			t.Errorf("GetCrawlRequests(%d, %d) next = %v, previous = %v", tt.page, tt.pageSize, list.Next, list.Previous)
+		}
+	}
+
+	if _, err := client.GetCrawlRequests(ctx, 4, 10); !errors.Is(err, watercrawl.ErrNotFound) {
+		t.Errorf("GetCrawlRequests(4, 10) error = %v, want %v", err, watercrawl.ErrNotFound)
+	}
+
+	seen := map[string]bool{}
+	it := client.IterateCrawlRequests(watercrawl.IteratorOptions{PageSize: 7})
+	for it.Next(ctx) {
+		seen[it.Value().UUID] = true
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("IterateCrawlRequests() error = %v", err)
+	}
+	if len(seen) != 25 {
+		t.Errorf("IterateCrawlRequests() returned %d requests, want 25", len(seen))
+	}
+}
+
+func TestServer_Faults(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	server.AddFault(Fault{
+		Method: http.MethodGet,
+		Path:   "core/crawl-requests/",
+		Status: http.StatusTooManyRequests,
+		Header: http.Header{"Retry-After": {"1"}},
+		Times:  1,
+	})
+	server.AddFault(Fault{Path: "core/crawl-requests/slow/", Delay: 50 * time.Millisecond, Status: http.StatusNotFound})
+	server.AddFault(Fault{Path: "core/crawl-requests/broken/", Abort: true})
+
+	client := server.NewClient()
+	ctx := context.Background()
+
+	var apiErr *watercrawl.APIError
+	_, err := client.GetCrawlRequests(ctx, 1, 10)
+	if !errors.Is(err, watercrawl.ErrRateLimited) || !errors.As(err, &apiErr) || apiErr.Header.Get("Retry-After") != "1" {
+		t.Errorf("GetCrawlRequests() error = %v, want %v with Retry-After", err, watercrawl.ErrRateLimited)
+	}
+	if _, err := client.GetCrawlRequests(ctx, 1, 10); err != nil {
+		t.Errorf("GetCrawlRequests() after the fault error = %v", err)
+	}
+
+	start := time.Now()
+	if _, err := client.GetCrawlRequest(ctx, "slow"); !errors.Is(err, watercrawl.ErrNotFound) {
+		t.Errorf("GetCrawlRequest(slow) error = %v, want %v", err, watercrawl.ErrNotFound)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("GetCrawlRequest(slow) took %s, want at least 50ms", elapsed)
+	}
+
+	if _, err := client.GetCrawlRequest(ctx, "broken"); err == nil || errors.As(err, &apiErr) {
+		t.Errorf("GetCrawlRequest(broken) error = %v, want a transport error", err)
+	}
+
+	if got := len(server.RequestsMatching(http.MethodGet, "core/crawl-requests/")); got != 2 {
+		t.Errorf("recorded list requests = %d, want 2", got)
+	}
+}
+
+func TestServer_APIKey(t *testing.T) {
+	server := NewServer(WithAPIKey("secret"))
+	defer server.Close()
+
+	ctx := context.Background()
+	if _, err := server.NewClient().GetCrawlRequests(ctx, 1, 10); err != nil {
+		t.Errorf("GetCrawlRequests() error = %v", err)
+	}
+
+	client := watercrawl.NewClient("wrong", server.URL+"/")
+	if _, err := client.GetCrawlRequests(ctx, 1, 10); !errors.Is(err, watercrawl.ErrUnauthorized) {
+		t.Errorf("GetCrawlRequests() with a wrong key error = %v, want %v", err, watercrawl.ErrUnauthorized)
+	}
+}
+
+func TestServer_IdempotencyKey(t *testing.T) {
+	server := NewServer(WithEventInterval(time.Hour))
+	defer server.Close()
+
+	client := server.NewClient()
+	input := watercrawl.CreateCrawlRequestInput{
+		URL:            watercrawl.URLs{"https://example.com"},
+		IdempotencyKey: "key-1",
+	}
+
+	first, err := client.CreateCrawlRequest(context.Background(), input)
+	if err != nil {
+		t.Fatalf("CreateCrawlRequest() error = %v", err)
+	}
+	second, err := client.CreateCrawlRequest(context.Background(), input)
+	if err != nil {
+		t.Fatalf("CreateCrawlRequest() error = %v", err)
+	}
+	if first.UUID != second.UUID {
+		t.Errorf("CreateCrawlRequest() with the same key = %s and %s, want the same request", first.UUID, second.UUID)
+	}
+}
+
+func TestServer_CreateValidation(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/api/v1/core/crawl-requests/", "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("POST without body status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}